@@ -11,7 +11,8 @@ import (
 )
 
 const (
-	orderbookAddr = "localhost:50051" // Orderbook service gRPC endpoint
+	orderbookAddr   = "localhost:50051" // Orderbook service gRPC endpoint
+	matchStartDelay = 30 * time.Minute  // How long after registration a match starts
 )
 
 func main() {
@@ -44,9 +45,10 @@ func createAndRegisterMatch(client orderbookpb.OrderbookServiceClient) {
 	log.Printf("Creating new match: %s vs %s (ID: %s)", teamA, teamB, matchID)
 
 	req := &orderbookpb.MatchRequest{
-		MatchId: matchID,
-		TeamA:   teamA,
-		TeamB:   teamB,
+		MatchId:   matchID,
+		TeamA:     teamA,
+		TeamB:     teamB,
+		StartTime: time.Now().Add(matchStartDelay).Unix(),
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -59,5 +61,28 @@ func createAndRegisterMatch(client orderbookpb.OrderbookServiceClient) {
 	}
 
 	log.Printf("Match registered successfully: %s", resp.Status)
+
+	// Markets are registered PENDING; open this one for trading now and
+	// take it in-play when the match starts.
+	transitionMarket(ctx, client, matchID, "OPEN", "match created")
+	time.AfterFunc(matchStartDelay, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		transitionMarket(ctx, client, matchID, "IN_PLAY", "match started")
+	})
 }
-// 770b8b49-027b-46ec-b427-d45b80e0a137
\ No newline at end of file
+
+func transitionMarket(ctx context.Context, client orderbookpb.OrderbookServiceClient, matchID, state, reason string) {
+	resp, err := client.TransitionMarket(ctx, &orderbookpb.TransitionMarketRequest{
+		MatchId: matchID,
+		State:   state,
+		Reason:  reason,
+	})
+	if err != nil {
+		log.Printf("failed to move match %s to %s: %v", matchID, state, err)
+		return
+	}
+	log.Printf("Match %s: %s -> %s", matchID, resp.PreviousState, resp.State)
+}
+
+// 770b8b49-027b-46ec-b427-d45b80e0a137
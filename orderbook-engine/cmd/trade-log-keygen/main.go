@@ -0,0 +1,30 @@
+// Command trade-log-keygen creates the key the engine signs its trade log
+// with. Give the engine the private key with -trade-log-key and verifiers
+// the public key; keep the private key off the volume the log is on.
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"log"
+
+	"github.com/amithshubhan/Bet_Now/orderbook-engine/tradelog"
+)
+
+func main() {
+	keyPath := flag.String("key", "", "where to write the private key (required)")
+	pubPath := flag.String("pubkey", "", "where to write the public key (default: the key's path + \".pub\")")
+	flag.Parse()
+	if *keyPath == "" {
+		log.Fatal("-key is required")
+	}
+	if *pubPath == "" {
+		*pubPath = *keyPath + ".pub"
+	}
+
+	pub, err := tradelog.GenerateKey(*keyPath, *pubPath)
+	if err != nil {
+		log.Fatalf("failed to generate key: %v", err)
+	}
+	log.Printf("Wrote %s and %s (public key %s)", *keyPath, *pubPath, hex.EncodeToString(pub))
+}
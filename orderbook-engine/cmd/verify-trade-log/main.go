@@ -0,0 +1,54 @@
+// Command verify-trade-log checks a trade log written by the engine: every
+// entry must chain onto the one before it, every checkpoint must be signed
+// by the engine's key, and the log must reach its signed head. It exits
+// non-zero on the first broken entry.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/amithshubhan/Bet_Now/orderbook-engine/tradelog"
+)
+
+func main() {
+	logPath := flag.String("log", "data/trades.log", "trade log to verify")
+	headPath := flag.String("head", "", "signed head of the log (default: the log's path + \".head\")")
+	keyPath := flag.String("pubkey", "", "hex-encoded ed25519 public key of the engine (required)")
+	flag.Parse()
+	if *keyPath == "" {
+		log.Fatal("-pubkey is required")
+	}
+	if *headPath == "" {
+		*headPath = tradelog.HeadPath(*logPath)
+	}
+
+	pub, err := tradelog.LoadPublicKey(*keyPath)
+	if err != nil {
+		log.Fatalf("failed to load public key: %v", err)
+	}
+	head, err := tradelog.ReadHead(*headPath, pub)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Fatalf("failed to read signed head: %v", err)
+	}
+	file, err := os.Open(*logPath)
+	if err != nil {
+		log.Fatalf("failed to open trade log: %v", err)
+	}
+	defer file.Close()
+
+	report, err := tradelog.Verify(file, pub, head)
+	out := json.NewEncoder(os.Stdout)
+	out.SetIndent("", "  ")
+	out.Encode(report)
+	if err != nil {
+		log.Fatalf("verification failed: %v", err)
+	}
+	if report.Unsigned > 0 {
+		log.Printf("%d records after the last checkpoint were written but never acknowledged", report.Unsigned)
+	}
+	log.Printf("%s verified: %d records, %d checkpoints", *logPath, report.Records, report.Checkpoints)
+}
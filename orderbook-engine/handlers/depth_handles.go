@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/amithshubhan/Bet_Now/orderbook-engine/orderbook"
+)
+
+// MarketDepthHandler serves GET /depth?match_id=...&team_id=...&levels=...
+func MarketDepthHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	query := r.URL.Query()
+	levels := 0
+	if s := query.Get("levels"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			http.Error(w, "invalid levels: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		levels = n
+	}
+
+	depth, err := orderbook.GetMarketDepth(query.Get("match_id"), query.Get("team_id"), levels)
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, depth)
+}
@@ -2,26 +2,125 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/amithshubhan/Bet_Now/orderbook-engine/orderbook"
 )
 
+// PlaceOrderHandler serves POST /place-order with an orderbook.Order. Side "bid"
+// backs team_id and "ask" lays it; price is decimal odds, the lowest a back
+// will take and the highest a lay will give.
 func PlaceOrderHandler(w http.ResponseWriter, r *http.Request) {
-    var order orderbook.Order
-    if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
-        http.Error(w, "invalid input", http.StatusBadRequest)
-        return
-    }
-	
-    go orderbook.PlaceOrder(order) // goroutine or channel-driven
-    w.WriteHeader(http.StatusAccepted)
+	var order orderbook.Order
+	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+		http.Error(w, "invalid input: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := orderbook.PlaceOrder(order)
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+type CancelOrderRequest struct {
+	MatchID string `json:"match_id"`
+	OrderID string `json:"order_id"`
+	UserID  string `json:"user_id"`
+}
+
+type CancelOrderResponse struct {
+	OrderID           string          `json:"order_id"`
+	RemainingQuantity orderbook.Money `json:"remaining_quantity"`
+}
+
+func CancelOrderHandler(w http.ResponseWriter, r *http.Request) {
+	var req CancelOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid input: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	remaining, err := orderbook.CancelOrder(req.MatchID, req.OrderID, req.UserID)
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, CancelOrderResponse{
+		OrderID:           req.OrderID,
+		RemainingQuantity: remaining,
+	})
+}
+
+type AmendOrderRequest struct {
+	MatchID  string          `json:"match_id"`
+	OrderID  string          `json:"order_id"`
+	UserID   string          `json:"user_id"`
+	Price    orderbook.Odds  `json:"price"`
+	Quantity orderbook.Money `json:"quantity"`
+}
+
+func AmendOrderHandler(w http.ResponseWriter, r *http.Request) {
+	var req AmendOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid input: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	amended, err := orderbook.AmendOrder(req.MatchID, req.OrderID, req.UserID, req.Price, req.Quantity)
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, amended)
+}
+
+// statusFor maps engine errors onto HTTP status codes.
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, orderbook.ErrOrderNotFound), errors.Is(err, orderbook.ErrMatchNotFound),
+		errors.Is(err, orderbook.ErrUnknownRunner):
+		return http.StatusNotFound
+	case errors.Is(err, orderbook.ErrMarketNotOpen), errors.Is(err, orderbook.ErrInsufficientFunds),
+		errors.Is(err, orderbook.ErrExposureLimit), errors.Is(err, orderbook.ErrNothingToCashOut),
+		errors.Is(err, orderbook.ErrNoLiquidity), errors.Is(err, orderbook.ErrOrderRejected),
+		errors.Is(err, orderbook.ErrOrderExpired):
+		return http.StatusConflict
+	case errors.Is(err, orderbook.ErrMatchExists):
+		return http.StatusConflict
+	case errors.Is(err, orderbook.ErrMarketBusy), errors.Is(err, orderbook.ErrEngineHalted):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, orderbook.ErrNotOrderOwner):
+		return http.StatusForbidden
+	case errors.Is(err, orderbook.ErrInvalidAmendment), errors.Is(err, orderbook.ErrOffLadder),
+		errors.Is(err, orderbook.ErrMissingUser), errors.Is(err, orderbook.ErrInvalidAmount),
+		errors.Is(err, orderbook.ErrInvalidTolerance), errors.Is(err, orderbook.ErrInvalidRunners):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
 }
 
 type Match struct {
 	MatchID string `json:"match_id"`
 	TeamA   string `json:"team_a"`
 	TeamB   string `json:"team_b"`
+
+	// Runners lists every runner of a market with more than two, such as
+	// home, draw and away. It replaces TeamA and TeamB when set.
+	Runners []string `json:"runners,omitempty"`
+
+	StartTime time.Time `json:"start_time"`
 }
 
 func RegisterMatchHandler(w http.ResponseWriter, r *http.Request) {
@@ -30,7 +129,14 @@ func RegisterMatchHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "invalid input", http.StatusBadRequest)
 		return
 	}
-	
-	go orderbook.RegisterMatch(match.MatchID, match.TeamA, match.TeamB) // goroutine or channel-driven
+
+	runners := match.Runners
+	if len(runners) == 0 {
+		runners = []string{match.TeamA, match.TeamB}
+	}
+	if err := orderbook.RegisterMarket(match.MatchID, runners, match.StartTime, orderbook.MarketOptions{}); err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
 	w.WriteHeader(http.StatusAccepted)
 }
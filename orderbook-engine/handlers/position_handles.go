@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/amithshubhan/Bet_Now/orderbook-engine/orderbook"
+)
+
+// PositionHandler serves GET /position?match_id=...&user_id=...
+func PositionHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	position, err := orderbook.GetPosition(query.Get("match_id"), query.Get("user_id"))
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, position)
+}
+
+// CashOutQuoteHandler serves GET /cash-out/quote?match_id=...&user_id=...
+func CashOutQuoteHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	quote, err := orderbook.GetCashOutQuote(query.Get("match_id"), query.Get("user_id"))
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, quote)
+}
+
+type CashOutRequest struct {
+	MatchID        string `json:"match_id"`
+	UserID         string `json:"user_id"`
+	ToleranceTicks int    `json:"tolerance_ticks"`
+}
+
+func CashOutHandler(w http.ResponseWriter, r *http.Request) {
+	var req CashOutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid input: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := orderbook.CashOut(req.MatchID, req.UserID, req.ToleranceTicks)
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/amithshubhan/Bet_Now/orderbook-engine/orderbook"
+)
+
+// SnapshotHandler serves POST /snapshot, writing a snapshot of every
+// market to dir on demand.
+func SnapshotHandler(dir string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		path, err := orderbook.WriteSnapshot(dir)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"path": path})
+	}
+}
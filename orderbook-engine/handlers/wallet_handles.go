@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/amithshubhan/Bet_Now/orderbook-engine/orderbook"
+)
+
+type WalletTransferRequest struct {
+	UserID string          `json:"user_id"`
+	Amount orderbook.Money `json:"amount"`
+}
+
+func DepositHandler(w http.ResponseWriter, r *http.Request) {
+	transferHandler(w, r, orderbook.Deposit)
+}
+
+func WithdrawHandler(w http.ResponseWriter, r *http.Request) {
+	transferHandler(w, r, orderbook.Withdraw)
+}
+
+func transferHandler(w http.ResponseWriter, r *http.Request, transfer func(string, orderbook.Money) (orderbook.Wallet, error)) {
+	var req WalletTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid input: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	wallet, err := transfer(req.UserID, req.Amount)
+	if err != nil {
+		http.Error(w, err.Error(), statusFor(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, wallet)
+}
+
+// WalletHandler serves GET /wallet?user_id=...
+func WalletHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		http.Error(w, orderbook.ErrMissingUser.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, http.StatusOK, orderbook.GetWallet(userID))
+}
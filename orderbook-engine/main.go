@@ -1,16 +1,68 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"log"
 	"net"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/amithshubhan/Bet_Now/orderbook-engine/handlers"
+	"github.com/amithshubhan/Bet_Now/orderbook-engine/orderbook"
+	"github.com/amithshubhan/Bet_Now/orderbook-engine/tradelog"
 	"github.com/amithshubhan/Bet_Now/orderbookpb"
 	"google.golang.org/grpc"
 )
 
+// Snapshots of every market are written here periodically and on demand;
+// the newest is loaded on startup, then the command log is replayed from
+// where it left off.
+const (
+	snapshotDir      = "data/snapshots"
+	snapshotInterval = time.Minute
+	commandLogPath   = "data/commands.log"
+)
+
+// Every trade is written to a hash-chained log, signed at checkpoints with
+// the key given by -trade-log-key; cmd/verify-trade-log checks it against
+// the public key cmd/trade-log-keygen wrote with it.
+const tradeLogPath = "data/trades.log"
 
+var (
+	replayPath   = flag.String("replay", "", "replay this command log, print every result and exit")
+	snapshotPath = flag.String("snapshot", "", "with -replay, start from this snapshot instead of an empty engine")
+	printBooks   = flag.Bool("print-books", false, "print a match's order books after every order, for debugging")
+	tradeLogKey  = flag.String("trade-log-key", "", "private key that signs the trade log, kept apart from data/ (required to serve)")
+)
+
+// replay rebuilds the engine from a command log without serving, printing
+// each command's result as a JSON line so two runs can be diffed.
+func replay(path, snapshot string) {
+	if snapshot != "" {
+		if err := orderbook.LoadSnapshot(snapshot); err != nil {
+			log.Fatalf("failed to restore snapshot: %v", err)
+		}
+	}
+	out := json.NewEncoder(os.Stdout)
+	n, err := orderbook.ReplayCommands(path, func(cmd orderbook.Command, result any, err error) {
+		line := struct {
+			Seq    uint64 `json:"seq"`
+			Type   string `json:"type"`
+			Result any    `json:"result,omitempty"`
+			Error  string `json:"error,omitempty"`
+		}{Seq: cmd.Seq, Type: cmd.Type, Result: result}
+		if err != nil {
+			line.Error = err.Error()
+		}
+		out.Encode(line)
+	})
+	if err != nil {
+		log.Fatalf("replay stopped after %d commands: %v", n, err)
+	}
+	log.Printf("Replayed %d commands from %s", n, path)
+}
 
 func startGRPCServer(grpcServer *grpc.Server, listener net.Listener) {
 	log.Printf("Starting gRPC server on %s", listener.Addr().String())
@@ -22,7 +74,17 @@ func startGRPCServer(grpcServer *grpc.Server, listener net.Listener) {
 func startHTTPServer(addr string) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/place-order", handlers.PlaceOrderHandler)
-	
+	mux.HandleFunc("/cancel-order", handlers.CancelOrderHandler)
+	mux.HandleFunc("/amend-order", handlers.AmendOrderHandler)
+	mux.HandleFunc("/deposit", handlers.DepositHandler)
+	mux.HandleFunc("/withdraw", handlers.WithdrawHandler)
+	mux.HandleFunc("/wallet", handlers.WalletHandler)
+	mux.HandleFunc("/position", handlers.PositionHandler)
+	mux.HandleFunc("/depth", handlers.MarketDepthHandler)
+	mux.HandleFunc("/cash-out/quote", handlers.CashOutQuoteHandler)
+	mux.HandleFunc("/cash-out", handlers.CashOutHandler)
+	mux.HandleFunc("/snapshot", handlers.SnapshotHandler(snapshotDir))
+
 	log.Printf("Starting HTTP server on %s", addr)
 	if err := http.ListenAndServe(addr, mux); err != nil {
 		log.Fatalf("failed to serve HTTP: %v", err)
@@ -30,6 +92,40 @@ func startHTTPServer(addr string) {
 }
 
 func main() {
+	flag.Parse()
+	orderbook.SetPrintBooks(*printBooks)
+	if *replayPath != "" {
+		replay(*replayPath, *snapshotPath)
+		return
+	}
+
+	if err := os.MkdirAll("data", 0o755); err != nil {
+		log.Fatalf("failed to create data directory: %v", err)
+	}
+	if *tradeLogKey == "" {
+		log.Fatal("-trade-log-key is required: create one with cmd/trade-log-keygen")
+	}
+	key, err := tradelog.LoadKey(*tradeLogKey)
+	if err != nil {
+		log.Fatalf("failed to load trade log key: %v", err)
+	}
+
+	// Restore the order books from the last snapshot before serving
+	if path, err := orderbook.LoadLatestSnapshot(snapshotDir); err != nil {
+		log.Fatalf("failed to restore snapshot: %v", err)
+	} else if path != "" {
+		log.Printf("Loaded snapshot %s", path)
+	}
+	if err := orderbook.OpenTradeLog(tradeLogPath, key); err != nil {
+		log.Fatalf("failed to open trade log: %v", err)
+	}
+	// ...and every command accepted since it was taken
+	if n, err := orderbook.OpenCommandLog(commandLogPath); err != nil {
+		log.Fatalf("failed to open command log: %v", err)
+	} else if n > 0 {
+		log.Printf("Replayed %d commands from %s", n, commandLogPath)
+	}
+
 	// Initialize gRPC server
 	listener, err := net.Listen("tcp", ":50051")
 	if err != nil {
@@ -39,6 +135,10 @@ func main() {
 	grpcServer := grpc.NewServer()
 	orderbookpb.RegisterOrderbookServiceServer(grpcServer, &orderbookServer{})
 
+	// Expire GTD orders that have reached their expiry or match start
+	orderbook.StartExpirySweeper(time.Second)
+	orderbook.StartSnapshotter(snapshotDir, snapshotInterval)
+
 	// Start servers in goroutines
 	go startGRPCServer(grpcServer, listener)
 	go startHTTPServer(":8081")
@@ -0,0 +1,180 @@
+package orderbook
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// The benchmarks compare PriceLevels with the binary heap it replaced, on a
+// book of benchOrders lays spread over benchLevels ladder ticks.
+const (
+	benchOrders = 10000
+	benchLevels = 100
+)
+
+// heapBook is one side of the old book: a heap in price-time priority.
+type heapBook struct{ *Heap[*Order] }
+
+func newHeapBook() heapBook {
+	return heapBook{New[*Order](func(a, b *Order) bool {
+		if a.Price != b.Price {
+			return a.Price > b.Price
+		}
+		return a.Seq < b.Seq
+	})}
+}
+
+// depth sorts a copy of the heap, which is the only way to walk it in
+// price order.
+func (h heapBook) depth(levels int) []DepthLevel {
+	orders := append([]*Order(nil), h.Items()...)
+	sort.Slice(orders, func(i, j int) bool { return h.less(orders[i], orders[j]) })
+	var out []DepthLevel
+	for _, order := range orders {
+		if n := len(out); n == 0 || out[n-1].Price != order.Price {
+			if n == levels {
+				break
+			}
+			out = append(out, DepthLevel{Price: order.Price})
+		}
+		out[len(out)-1].Quantity += order.Quantity
+		out[len(out)-1].Orders++
+	}
+	return out
+}
+
+func levelsDepth(s *PriceLevels, levels int) []DepthLevel {
+	var out []DepthLevel
+	s.eachLevel(func(level *priceLevel) bool {
+		l := DepthLevel{Price: level.price, Orders: level.orders.Len()}
+		for e := level.orders.Front(); e != nil; e = e.Next() {
+			l.Quantity += e.Value.(*Order).Quantity
+		}
+		out = append(out, l)
+		return len(out) < levels
+	})
+	return out
+}
+
+// benchBook returns benchOrders lays in acceptance order, with prices
+// drawn from the first benchLevels ticks of the ladder.
+func benchBook(b *testing.B) []*Order {
+	b.Helper()
+	ladder := OddsLadder()
+	rng := rand.New(rand.NewSource(1))
+	orders := make([]*Order, benchOrders)
+	for i := range orders {
+		price, ok := ladder.At(rng.Intn(benchLevels))
+		if !ok {
+			b.Fatalf("ladder has fewer than %d ticks", benchLevels)
+		}
+		orders[i] = &Order{Seq: uint64(i + 1), Side: "ask", Price: price, Quantity: 100}
+	}
+	return orders
+}
+
+func BenchmarkBookInsert(b *testing.B) {
+	orders := benchBook(b)
+	b.Run("heap", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			h := newHeapBook()
+			for _, order := range orders {
+				h.Push(order)
+			}
+		}
+	})
+	b.Run("levels", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			s := newPriceLevels(askPriority)
+			for _, order := range orders {
+				s.Push(order)
+			}
+		}
+	})
+}
+
+func BenchmarkBookCancel(b *testing.B) {
+	orders := benchBook(b)
+	cancels := append([]*Order(nil), orders...)
+	rand.New(rand.NewSource(2)).Shuffle(len(cancels), func(i, j int) {
+		cancels[i], cancels[j] = cancels[j], cancels[i]
+	})
+	b.Run("heap", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			h := newHeapBook()
+			for _, order := range orders {
+				h.Push(order)
+			}
+			b.StartTimer()
+			for _, order := range cancels {
+				h.Remove(order)
+			}
+		}
+	})
+	b.Run("levels", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			s := newPriceLevels(askPriority)
+			for _, order := range orders {
+				s.Push(order)
+			}
+			b.StartTimer()
+			for _, order := range cancels {
+				s.Remove(order)
+			}
+		}
+	})
+}
+
+// BenchmarkBookBest drains the book from the top, as a large taker would.
+func BenchmarkBookBest(b *testing.B) {
+	orders := benchBook(b)
+	b.Run("heap", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			h := newHeapBook()
+			for _, order := range orders {
+				h.Push(order)
+			}
+			b.StartTimer()
+			for h.Len() > 0 {
+				h.Remove(h.Peek())
+			}
+		}
+	})
+	b.Run("levels", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			s := newPriceLevels(askPriority)
+			for _, order := range orders {
+				s.Push(order)
+			}
+			b.StartTimer()
+			for s.Len() > 0 {
+				s.Remove(s.Peek())
+			}
+		}
+	})
+}
+
+func BenchmarkBookDepth(b *testing.B) {
+	orders := benchBook(b)
+	h := newHeapBook()
+	s := newPriceLevels(askPriority)
+	for _, order := range orders {
+		h.Push(order)
+		s.Push(order)
+	}
+	b.Run("heap", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			h.depth(DefaultDepthLevels)
+		}
+	})
+	b.Run("levels", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			levelsDepth(s, DefaultDepthLevels)
+		}
+	})
+}
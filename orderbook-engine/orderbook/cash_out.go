@@ -0,0 +1,250 @@
+package orderbook
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// CashOutQuote is the single order that levels a user's position as far as
+// the books allow right now.
+type CashOutQuote struct {
+	MatchID   string   `json:"match_id"`
+	UserID    string   `json:"user_id"`
+	TeamID    string   `json:"team_id"`
+	Side      string   `json:"side"`      // "bid" backs TeamID, "ask" lays it
+	Price     Odds     `json:"price"`     // best price in TeamID's book
+	Stake     Money    `json:"stake"`     // backer's stake of the order
+	Available Money    `json:"available"` // stake on offer at Price
+	Profit    Money    `json:"profit"`    // guaranteed: the worst outcome once filled
+	After     Position `json:"after"`     // the position once filled at Price
+}
+
+// CashOutResult is a cash-out order and the position it left.
+type CashOutResult struct {
+	Quote    CashOutQuote    `json:"quote"`
+	Report   ExecutionReport `json:"report"`
+	Position Position        `json:"position"`
+}
+
+// GetCashOutQuote works out the order that equalises a user's profit
+// across outcomes at the best prices in the books. In a market with more
+// than two runners one order can only level its own runner against the
+// worst of the rest; the quote picks the runner that leaves the best
+// guaranteed result.
+func GetCashOutQuote(matchID, userID string) (CashOutQuote, error) {
+	if userID == "" {
+		return CashOutQuote{}, ErrMissingUser
+	}
+	m, err := lookupMarket(matchID)
+	if err != nil {
+		return CashOutQuote{}, err
+	}
+	return execute(m, func() (CashOutQuote, error) {
+		return m.cashOutQuote(userID, now())
+	})
+}
+
+// CashOut quotes and places the cash-out order in one step, as a market
+// order that accepts prices up to toleranceTicks ladder ticks worse than
+// the quote. Whatever cannot be filled within that is discarded.
+func CashOut(matchID, userID string, toleranceTicks int) (CashOutResult, error) {
+	if userID == "" {
+		return CashOutResult{}, ErrMissingUser
+	}
+	if toleranceTicks < 0 {
+		return CashOutResult{}, fmt.Errorf("%w: %d", ErrInvalidTolerance, toleranceTicks)
+	}
+	m, err := lookupMarket(matchID)
+	if err != nil {
+		return CashOutResult{}, err
+	}
+	cmd := Command{Type: CommandCashOut, MatchID: m.id, UserID: userID, Ticks: toleranceTicks}
+	return marketCommand(m, cmd, func() (CashOutResult, error) {
+		return m.cashOut(userID, toleranceTicks)
+	})
+}
+
+func (m *market) cashOut(userID string, toleranceTicks int) (CashOutResult, error) {
+	quote, err := m.cashOutQuote(userID, m.cmd.at)
+	if err != nil {
+		return CashOutResult{}, err
+	}
+	log.Printf("Cash-out for %s in match %s: %s %s on %s at %s",
+		userID, m.id, quote.Side, quote.Stake, quote.TeamID, quote.Price)
+
+	report := m.placeOrder(Order{
+		MatchID:    m.id,
+		TeamID:     quote.TeamID,
+		UserID:     userID,
+		Side:       quote.Side,
+		Type:       OrderTypeMarket,
+		WorstPrice: worsePrice(quote.Price, quote.Side, toleranceTicks),
+		Quantity:   quote.Stake,
+	})
+	if report.Status == StatusRejected {
+		return CashOutResult{}, rejectError(report)
+	}
+	return CashOutResult{
+		Quote:    quote,
+		Report:   report,
+		Position: m.view(userID, m.positionOrFlat(userID)),
+	}, nil
+}
+
+// cashOutQuote prices the cash-out against the books as of at.
+func (m *market) cashOutQuote(userID string, at time.Time) (CashOutQuote, error) {
+	p := m.positionOrFlat(userID)
+	if m.level(p) {
+		return CashOutQuote{}, ErrNothingToCashOut
+	}
+
+	var best *CashOutQuote
+	for _, runner := range m.runners {
+		quote, ok := m.levelOn(userID, p, runner, at)
+		if ok && (best == nil || quote.Profit > best.Profit) {
+			best = &quote
+		}
+	}
+	if best == nil {
+		return CashOutQuote{}, ErrNoLiquidity
+	}
+	return *best, nil
+}
+
+// levelOn quotes the order on runner that lifts the position's worst
+// outcome the most: backing runner when it is the weaker side, laying it
+// when it is the stronger one.
+func (m *market) levelOn(userID string, p *position, runner string, at time.Time) (CashOutQuote, bool) {
+	ifWins := p.ifWins(runner)
+	rest := m.worstOutcome(p, runner)
+	if ifWins == rest {
+		return CashOutQuote{}, false
+	}
+
+	// Backing s at o moves runner by s·(o-1) and the rest by -s; laying is
+	// the reverse. Either way the outcomes meet at s = gap/o.
+	side, gap := "bid", rest-ifWins
+	if ifWins > rest {
+		side, gap = "ask", ifWins-rest
+	}
+	price, available := m.bestOffer(runner, side, userID, at)
+	if price == 0 {
+		return CashOutQuote{}, false
+	}
+	stake := gap.DivOddsDown(price)
+	if stake <= 0 {
+		return CashOutQuote{}, false
+	}
+
+	leg := TradeLeg{UserID: userID, TeamID: runner, Side: side, Odds: price, Risk: stake, Win: stake.Liability(price)}
+	if side == "ask" {
+		leg.Risk, leg.Win = leg.Win, leg.Risk
+	}
+	after := p.clone()
+	after.add(leg, m.runners)
+	return CashOutQuote{
+		MatchID:   m.id,
+		UserID:    userID,
+		TeamID:    runner,
+		Side:      side,
+		Price:     price,
+		Stake:     stake,
+		Available: available,
+		Profit:    m.worstOutcome(after, ""),
+		After:     m.view(userID, after),
+	}, true
+}
+
+// level reports whether the position pays the same whoever wins.
+func (m *market) level(p *position) bool {
+	for _, runner := range m.runners[1:] {
+		if p.ifWins(runner) != p.ifWins(m.runners[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// worstOutcome is the position's lowest profit over every runner except
+// excluded.
+func (m *market) worstOutcome(p *position, excluded string) Money {
+	first := true
+	var worst Money
+	for _, runner := range m.runners {
+		if runner == excluded {
+			continue
+		}
+		if pnl := p.ifWins(runner); first || pnl < worst {
+			worst, first = pnl, false
+		}
+	}
+	return worst
+}
+
+// positionOrFlat returns the user's position, or an empty one.
+func (m *market) positionOrFlat(userID string) *position {
+	if p, ok := m.positions[userID]; ok {
+		return p
+	}
+	return newPosition()
+}
+
+// bestOffer returns the best price an order on side of runner could get at
+// time at, from runner's own book or by crossing the other runners' books,
+// ignoring the user's own orders, and the stake on offer at it. A cross
+// price is placed on the ladder as market depth places it. The price is 0
+// when there is nothing to take.
+func (m *market) bestOffer(runner, side, userID string, at time.Time) (Odds, Money) {
+	resting := m.books[runner].Asks // backs take lays
+	if side == "ask" {
+		resting = m.books[runner].Bids
+	}
+	var price Odds
+	var available Money
+	for _, order := range resting.Orders() {
+		if isExpired(order, at) || order.UserID == userID {
+			continue
+		}
+		if price != 0 && order.Price != price {
+			break
+		}
+		price = order.Price
+		available += order.Quantity
+	}
+
+	taker := Order{TeamID: runner, UserID: userID, Side: side, Type: OrderTypeMarket}
+	m.replayCross(taker, MaxMoney, at, func(legs []crossLeg) bool {
+		odds, ok := ladderPrice(legs[0].effectiveOdds(side), side)
+		switch {
+		case !ok:
+			return false
+		case price == 0 || resting.before(odds, price):
+			price, available = odds, legs[0].matched
+		case odds == price:
+			available += legs[0].matched
+		default:
+			return false
+		}
+		return true
+	})
+	return price, available
+}
+
+// worsePrice moves price ticks ladder ticks against an order on side:
+// lower odds for a back, higher for a lay. It stops at the end of the
+// ladder.
+func worsePrice(price Odds, side string, ticks int) Odds {
+	ladder := OddsLadder()
+	for ; ticks > 0; ticks-- {
+		next, ok := ladder.Next(price)
+		if side == "bid" {
+			next, ok = ladder.Prev(price)
+		}
+		if !ok {
+			break
+		}
+		price = next
+	}
+	return price
+}
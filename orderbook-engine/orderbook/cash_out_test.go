@@ -0,0 +1,63 @@
+package orderbook
+
+import (
+	"errors"
+	"testing"
+)
+
+// cashOutMarket gives a punter a back of a at 3.00 in a three-runner
+// market, and leaves lays of b and c resting at 3.00: the only way to lay a
+// is to cross them.
+func cashOutMarket(t *testing.T) (string, func(string) string) {
+	t.Helper()
+	matchID, user := openTestMarket(t, []string{"a", "b", "c"}, "punter", "layer", "maker")
+	place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("layer"), Side: "ask", Price: 3_00, Quantity: 10_00})
+	place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("punter"), Side: "bid", Price: 3_00, Quantity: 10_00})
+	for _, runner := range []string{"b", "c"} {
+		place(t, Order{MatchID: matchID, TeamID: runner, UserID: user("maker"), Side: "ask", Price: 3_00, Quantity: 100_00})
+	}
+	return matchID, user
+}
+
+func TestCashOutAgainstCrossLiquidity(t *testing.T) {
+	matchID, user := cashOutMarket(t)
+
+	// Backing b or c only lifts one losing outcome; laying a through the
+	// cross levels all three.
+	quote, err := GetCashOutQuote(matchID, user("punter"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quote.TeamID != "a" || quote.Side != "ask" || quote.Price != 3_00 || quote.Stake != 10_00 {
+		t.Fatalf("quote: %+v", quote)
+	}
+	if quote.Available <= 0 || quote.Profit != 0 {
+		t.Errorf("quote: %+v", quote)
+	}
+
+	result, err := CashOut(matchID, user("punter"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Report.Status != StatusFilled || result.Report.Fills[0].MatchType != MatchCrossTeam {
+		t.Fatalf("report: %+v", result.Report)
+	}
+	for _, runner := range result.Position.Runners {
+		if runner.IfWins != 0 {
+			t.Errorf("after cash-out %s wins %s", runner.TeamID, runner.IfWins)
+		}
+	}
+	checkLedgerBalances(t)
+}
+
+func TestCashOutRejected(t *testing.T) {
+	matchID, user := cashOutMarket(t)
+
+	// With nothing left to cover the lay's liability the order is rejected.
+	if _, err := Withdraw(user("punter"), balanceOf(availableAccount(user("punter")))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CashOut(matchID, user("punter"), 0); !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("cash-out: %v", err)
+	}
+}
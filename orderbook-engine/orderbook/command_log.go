@@ -0,0 +1,508 @@
+package orderbook
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Command types, one for every call that changes engine state.
+const (
+	CommandRegister        = "register"
+	CommandPlaceOrder      = "place_order"
+	CommandCancelOrder     = "cancel_order"
+	CommandAmendOrder      = "amend_order"
+	CommandTransition      = "transition"
+	CommandSettle          = "settle"
+	CommandResettle        = "resettle"
+	CommandVoid            = "void"
+	CommandExpire          = "expire"
+	CommandDeposit         = "deposit"
+	CommandWithdraw        = "withdraw"
+	CommandSelfTradePolicy = "self_trade_policy"
+	CommandSurplusPolicy   = "surplus_policy"
+	CommandMaxExposure     = "max_exposure"
+	CommandCashOut         = "cash_out"
+)
+
+// Command is one accepted call as written to the command log. Only the
+// fields its Type uses are set.
+type Command struct {
+	Seq  uint64    `json:"seq"`
+	Type string    `json:"type"`
+	At   time.Time `json:"at"` // the engine clock while the command ran
+
+	MatchID  string      `json:"match_id,omitempty"`
+	Runners  []string    `json:"runners,omitempty"`
+	Start    time.Time   `json:"start,omitzero"`
+	Order    *Order      `json:"order,omitempty"`
+	OrderID  string      `json:"order_id,omitempty"`
+	UserID   string      `json:"user_id,omitempty"`
+	TeamID   string      `json:"team_id,omitempty"` // winner, for settle and resettle
+	Price    Odds        `json:"price,omitempty"`
+	Quantity Money       `json:"quantity,omitempty"`
+	Amount   Money       `json:"amount,omitempty"`
+	State    MarketState `json:"state,omitempty"`
+	Reason   string      `json:"reason,omitempty"`
+	Policy   string      `json:"policy,omitempty"`
+	Ticks    int         `json:"ticks,omitempty"`
+
+	Options *MarketOptions `json:"options,omitempty"` // for register
+}
+
+// recordFunds is the type of a fundsRecord in the command log.
+const recordFunds = "funds"
+
+// fundsRecord is a decision a command took on a user's available balance,
+// the one piece of state markets share. The original run applies markets'
+// commands side by side, so a decision can depend on commands logged after
+// it; a replay applies them one at a time and takes the recorded decision
+// instead of deciding again.
+type fundsRecord struct {
+	Type    string `json:"type"` // recordFunds
+	Ref     uint64 `json:"ref"`  // the command that took it
+	Granted bool   `json:"granted,omitempty"`
+}
+
+// stamp identifies the command a change belongs to. Its sequence number
+// names the orders and trades the command creates, and its time is the
+// engine clock while it runs, so a replay rebuilds both exactly.
+type stamp struct {
+	seq uint64
+	at  time.Time
+}
+
+// commandLog orders and records every state-changing command.
+//
+// A command is numbered, written and handed to its market in one short
+// critical section, so every market applies its commands in log order. The
+// markets then apply them side by side on their own goroutines. Each
+// decision taken on users' available balances is written as it is taken,
+// so replaying the log one command at a time rebuilds the same books,
+// trades and balances. Only the journal's numbering can differ, where the
+// original run interleaved the entries of markets working side by side;
+// every entry names the command that posted it.
+//
+// Records are group committed: a command's result is returned only once
+// its records, and every record written before them, are synced to disk,
+// and callers waiting together share one sync. A crash can lose commands
+// that had been applied but not yet acknowledged; never one that had.
+type commandLog struct {
+	admitting sync.Mutex
+	seq       uint64   // last command admitted
+	replay    *Command // logged command being replayed, if any
+
+	// Commands applied on the caller's goroutine hold inFlight shared
+	// from admission until they are applied, so a snapshot can wait for
+	// them.
+	inFlight sync.RWMutex
+
+	writing   sync.Mutex
+	file      *os.File          // nil when logging is off
+	written   uint64            // records written to file
+	decisions map[uint64][]bool // command → funds decisions to replay, while replaying
+
+	syncing sync.Mutex
+	synced  uint64 // records known to be on disk; guarded by syncing
+
+	halted atomic.Pointer[error]
+}
+
+var cmdLog commandLog
+
+// replaying is set while a log is replayed, so nothing is published twice.
+var replaying atomic.Bool
+
+// admit numbers cmd, writes it to the log and hands it to enqueue before
+// any later command is admitted. check runs first and may refuse the
+// command before anything is written. A replayed command keeps its logged
+// number and time and is not written again.
+func (l *commandLog) admit(cmd *Command, check func() error, enqueue func()) error {
+	l.admitting.Lock()
+	defer l.admitting.Unlock()
+	if err := l.haltErr(); err != nil {
+		return err
+	}
+	if check != nil {
+		if err := check(); err != nil {
+			return err
+		}
+	}
+	if r := l.replay; r != nil {
+		cmd.Seq, cmd.At = r.Seq, r.At
+	} else {
+		cmd.Seq = l.seq + 1
+		if cmd.At.IsZero() {
+			cmd.At = now()
+		}
+		if err := l.write(cmd); err != nil {
+			// A partly written record would corrupt every one after it.
+			l.halt(err)
+			return fmt.Errorf("logging %s command: %w", cmd.Type, err)
+		}
+	}
+	l.seq = cmd.Seq
+	enqueue()
+	return nil
+}
+
+// write appends record to the log as one line.
+func (l *commandLog) write(record any) error {
+	l.writing.Lock()
+	defer l.writing.Unlock()
+	return l.writeLocked(record)
+}
+
+func (l *commandLog) writeLocked(record any) error {
+	if l.file == nil {
+		return nil
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	l.written++
+	return nil
+}
+
+// commit returns once every record written so far is on disk. Callers
+// that arrive while a sync is running share the next one. A failed sync
+// halts the engine: it can no longer tell what a crash would keep.
+func (l *commandLog) commit() error {
+	l.writing.Lock()
+	target, file := l.written, l.file
+	l.writing.Unlock()
+	if file == nil {
+		return l.haltErr()
+	}
+
+	l.syncing.Lock()
+	defer l.syncing.Unlock()
+	if l.synced < target {
+		l.writing.Lock()
+		written := l.written
+		l.writing.Unlock()
+		if err := file.Sync(); err != nil {
+			l.halt(err)
+			return fmt.Errorf("syncing command log: %w", err)
+		}
+		l.synced = written
+	}
+	return l.haltErr()
+}
+
+// decideFunds decides whether command seq may take amount from a user who
+// has available, and records the decision. During a replay it returns the
+// decision the original run recorded; a command whose decision was lost
+// in a crash decides afresh. The caller holds the ledger lock, so
+// decisions are written in the order they were taken.
+func (l *commandLog) decideFunds(seq uint64, available, amount Money) bool {
+	l.writing.Lock()
+	defer l.writing.Unlock()
+	if recorded := l.decisions[seq]; len(recorded) > 0 {
+		l.decisions[seq] = recorded[1:]
+		return recorded[0]
+	}
+	granted := available >= amount
+	if l.decisions == nil {
+		if err := l.writeLocked(fundsRecord{Type: recordFunds, Ref: seq, Granted: granted}); err != nil {
+			l.halt(err)
+			return false
+		}
+	}
+	return granted
+}
+
+// halt stops the engine admitting commands after a failure that leaves
+// memory, the ledger or the logs out of step with each other.
+func (l *commandLog) halt(cause error) {
+	err := fmt.Errorf("%w: %w", ErrEngineHalted, cause)
+	if l.halted.CompareAndSwap(nil, &err) {
+		log.Printf("Engine halted: %v", cause)
+	}
+}
+
+// haltErr returns why the engine halted, or nil.
+func (l *commandLog) haltErr() error {
+	if err := l.halted.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// admitted returns the sequence number of the last command admitted.
+func (l *commandLog) admitted() uint64 {
+	l.admitting.Lock()
+	defer l.admitting.Unlock()
+	return l.seq
+}
+
+// marketCommand logs cmd and applies it on m's goroutine, after every
+// command logged for m before it. A full queue fails fast with
+// ErrMarketBusy before anything is logged.
+func marketCommand[T any](m *market, cmd Command, apply func() (T, error)) (T, error) {
+	var result T
+	var err error
+	done := make(chan struct{})
+	admitErr := cmdLog.admit(&cmd, func() error {
+		if len(m.commands) == cap(m.commands) {
+			return ErrMarketBusy
+		}
+		return nil
+	}, func() {
+		// The check left room, and the market drains its queue without
+		// waiting on admission, so a query that took the last slot in
+		// the meantime only delays this send.
+		m.commands <- func() {
+			defer close(done)
+			m.cmd, m.traded = stamp{cmd.Seq, cmd.At}, 0
+			result, err = apply()
+		}
+	})
+	if admitErr != nil {
+		var zero T
+		return zero, admitErr
+	}
+	<-done
+	if commitErr := cmdLog.commit(); commitErr != nil {
+		var zero T
+		return zero, commitErr
+	}
+	if commitErr := commitTrades(); commitErr != nil {
+		var zero T
+		return zero, commitErr
+	}
+	return result, err
+}
+
+// engineCommand logs cmd and applies it on the caller's goroutine, for the
+// commands that touch no market's books: registering a market and moving
+// money in or out. The ledger and the market map have locks of their own.
+func engineCommand[T any](cmd Command, apply func(stamp) (T, error)) (T, error) {
+	cmdLog.inFlight.RLock()
+	defer cmdLog.inFlight.RUnlock()
+	if err := cmdLog.admit(&cmd, nil, func() {}); err != nil {
+		var zero T
+		return zero, err
+	}
+	result, err := apply(stamp{cmd.Seq, cmd.At})
+	if commitErr := cmdLog.commit(); commitErr != nil {
+		var zero T
+		return zero, commitErr
+	}
+	return result, err
+}
+
+// OpenCommandLog replays any commands in the log at path that the engine
+// has not applied yet, such as those after the snapshot it was restored
+// from, then logs every further command to the end of it. It returns the
+// number of commands replayed. A log that ends before the snapshot, or is
+// missing, is refused: commands logged after it would follow a gap, and
+// the log could never again be replayed from scratch.
+func OpenCommandLog(path string) (int, error) {
+	replayed, end, err := replayCommands(path, nil)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return replayed, err
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return replayed, err
+	}
+	// Drop a record torn by a crash mid-write, so the next one starts on
+	// its own line.
+	if err := file.Truncate(end); err != nil {
+		file.Close()
+		return replayed, err
+	}
+	if _, err := file.Seek(end, io.SeekStart); err != nil {
+		file.Close()
+		return replayed, err
+	}
+
+	cmdLog.syncing.Lock()
+	defer cmdLog.syncing.Unlock()
+	cmdLog.writing.Lock()
+	defer cmdLog.writing.Unlock()
+	cmdLog.file, cmdLog.written, cmdLog.synced = file, 0, 0
+	return replayed, nil
+}
+
+// CloseCommandLog syncs and closes the command log; later commands are not
+// logged.
+func CloseCommandLog() error {
+	cmdLog.syncing.Lock()
+	defer cmdLog.syncing.Unlock()
+	cmdLog.writing.Lock()
+	defer cmdLog.writing.Unlock()
+	if cmdLog.file == nil {
+		return nil
+	}
+	err := cmdLog.file.Sync()
+	if closeErr := cmdLog.file.Close(); err == nil {
+		err = closeErr
+	}
+	cmdLog.file = nil
+	return err
+}
+
+// ReplayCommands applies every command in the log at path that the engine
+// has not applied yet, without logging them again. It works from an empty
+// engine or from one restored from a snapshot. observe, if not nil, is
+// called with each command and what applying it returned.
+func ReplayCommands(path string, observe func(cmd Command, result any, err error)) (int, error) {
+	replayed, _, err := replayCommands(path, observe)
+	return replayed, err
+}
+
+// replayCommands replays the log and returns the offset just past its last
+// complete record. The whole log is read first: a command's funds
+// decisions may be written after later commands.
+func replayCommands(path string, observe func(Command, any, error)) (int, int64, error) {
+	applied := cmdLog.admitted()
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) && applied > 0 {
+		return 0, 0, fmt.Errorf("%w: %v, but the engine has applied %d commands", ErrCorruptCommandLog, err, applied)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	var last uint64 // last command in the log
+	var pending []Command
+	decisions := make(map[uint64][]bool)
+	var end int64
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(bytes.TrimSpace(line)) > 0 {
+				log.Printf("Command log %s: ignoring torn record at offset %d", path, end)
+			}
+			break
+		}
+		if err != nil {
+			return 0, end, err
+		}
+
+		var cmd Command
+		if err := json.Unmarshal(line, &cmd); err != nil {
+			return 0, end, fmt.Errorf("%w at offset %d: %v", ErrCorruptCommandLog, end, err)
+		}
+		if cmd.Type == recordFunds {
+			var r fundsRecord
+			if err := json.Unmarshal(line, &r); err != nil {
+				return 0, end, fmt.Errorf("%w at offset %d: %v", ErrCorruptCommandLog, end, err)
+			}
+			if r.Ref > applied {
+				decisions[r.Ref] = append(decisions[r.Ref], r.Granted)
+			}
+		} else {
+			last = cmd.Seq
+			if cmd.Seq > applied {
+				pending = append(pending, cmd)
+			}
+		}
+		end += int64(len(line))
+	}
+	if last < applied {
+		return 0, end, fmt.Errorf("%w: the log ends at command %d, but the engine has applied %d", ErrCorruptCommandLog, last, applied)
+	}
+
+	replaying.Store(true)
+	defer replaying.Store(false)
+	cmdLog.writing.Lock()
+	cmdLog.decisions = decisions
+	cmdLog.writing.Unlock()
+	defer func() {
+		cmdLog.writing.Lock()
+		cmdLog.decisions = nil
+		cmdLog.writing.Unlock()
+	}()
+
+	var replayed int
+	for _, cmd := range pending {
+		if cmd.Seq != applied+1 {
+			return replayed, end, fmt.Errorf("%w: command %d follows %d", ErrCorruptCommandLog, cmd.Seq, applied)
+		}
+		result, err := replayCommand(cmd)
+		if cmdLog.admitted() != cmd.Seq {
+			return replayed, end, fmt.Errorf("%w: command %d (%s) was not applied: %v", ErrReplayDiverged, cmd.Seq, cmd.Type, err)
+		}
+		if observe != nil {
+			observe(cmd, result, err)
+		}
+		applied = cmd.Seq
+		replayed++
+	}
+	return replayed, end, checkReplayedTrades()
+}
+
+// replayCommand applies cmd through the same call that first accepted it.
+func replayCommand(cmd Command) (any, error) {
+	cmdLog.admitting.Lock()
+	cmdLog.replay = &cmd
+	cmdLog.admitting.Unlock()
+	defer func() {
+		cmdLog.admitting.Lock()
+		cmdLog.replay = nil
+		cmdLog.admitting.Unlock()
+	}()
+
+	switch cmd.Type {
+	case CommandRegister:
+		var opts MarketOptions
+		if cmd.Options != nil {
+			opts = *cmd.Options
+		}
+		return nil, RegisterMarket(cmd.MatchID, cmd.Runners, cmd.Start, opts)
+	case CommandPlaceOrder:
+		if cmd.Order == nil {
+			return nil, fmt.Errorf("%w: command %d has no order", ErrCorruptCommandLog, cmd.Seq)
+		}
+		return PlaceOrder(*cmd.Order)
+	case CommandCancelOrder:
+		return CancelOrder(cmd.MatchID, cmd.OrderID, cmd.UserID)
+	case CommandAmendOrder:
+		return AmendOrder(cmd.MatchID, cmd.OrderID, cmd.UserID, cmd.Price, cmd.Quantity)
+	case CommandTransition:
+		return TransitionMarket(cmd.MatchID, cmd.State, cmd.Reason)
+	case CommandSettle:
+		return SettleMatch(cmd.MatchID, cmd.TeamID)
+	case CommandResettle:
+		return ResettleMatch(cmd.MatchID, cmd.TeamID)
+	case CommandVoid:
+		return VoidMatch(cmd.MatchID, cmd.Reason)
+	case CommandExpire:
+		m, err := lookupMarket(cmd.MatchID)
+		if err != nil {
+			return nil, err
+		}
+		return m.expire(cmd.At)
+	case CommandDeposit:
+		return Deposit(cmd.UserID, cmd.Amount)
+	case CommandWithdraw:
+		return Withdraw(cmd.UserID, cmd.Amount)
+	case CommandSelfTradePolicy:
+		return nil, SetSelfTradePolicy(cmd.MatchID, SelfTradePolicy(cmd.Policy))
+	case CommandSurplusPolicy:
+		return nil, SetSurplusPolicy(cmd.MatchID, SurplusPolicy(cmd.Policy))
+	case CommandMaxExposure:
+		return nil, SetMaxExposure(cmd.MatchID, cmd.Amount)
+	case CommandCashOut:
+		return CashOut(cmd.MatchID, cmd.UserID, cmd.Ticks)
+	}
+	return nil, fmt.Errorf("%w: unknown command type %q", ErrCorruptCommandLog, cmd.Type)
+}
@@ -0,0 +1,206 @@
+package orderbook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// isolateEngine gives the test an empty engine with a clock it controls,
+// and puts the shared engine back when the test ends.
+func isolateEngine(t testing.TB) (advance func(time.Duration)) {
+	t.Helper()
+	savedMarkets, savedLedger, savedSeq, savedNow := markets, ledger, cmdLog.seq, now
+	clock := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	mu.Lock()
+	markets = make(map[string]*market)
+	mu.Unlock()
+	resetEngine()
+	t.Cleanup(func() {
+		resetEngine()
+		mu.Lock()
+		markets = savedMarkets
+		mu.Unlock()
+		ledger, cmdLog.seq, now = savedLedger, savedSeq, savedNow
+	})
+	return func(d time.Duration) { clock = clock.Add(d) }
+}
+
+// resetEngine stops every market and empties the engine.
+func resetEngine() {
+	mu.Lock()
+	for _, m := range markets {
+		close(m.commands)
+	}
+	markets = make(map[string]*market)
+	mu.Unlock()
+	ledger = &journal{balances: make(map[string]Money)}
+	cmdLog.admitting.Lock()
+	cmdLog.seq = 0
+	cmdLog.admitting.Unlock()
+}
+
+// engineState is a snapshot of the whole engine, less the time it was
+// taken, encoded for comparison.
+func engineState(t *testing.T) []byte {
+	t.Helper()
+	s := TakeSnapshot()
+	s.TakenAt = time.Time{}
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// TestReplayReproducesSession records a session of same-runner trades,
+// crosses, a GTD expiry, a cash-out and funds decisions across two
+// markets, then replays the log onto an empty engine and onto a snapshot
+// taken halfway through. Both must end with exactly the same trades,
+// ledger and books as the original run.
+func TestReplayReproducesSession(t *testing.T) {
+	advance := isolateEngine(t)
+	path := filepath.Join(t.TempDir(), "commands.log")
+	if _, err := OpenCommandLog(path); err != nil {
+		t.Fatal(err)
+	}
+	defer CloseCommandLog()
+
+	start := now().Add(time.Hour)
+	for _, reg := range []struct {
+		matchID string
+		runners []string
+	}{{"three-way", []string{"a", "b", "c"}}, {"head-to-head", []string{"x", "y"}}} {
+		if err := RegisterMarket(reg.matchID, reg.runners, start, MarketOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := TransitionMarket(reg.matchID, MarketOpen, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, user := range []string{"punter", "layer", "maker"} {
+		if _, err := Deposit(user, 1_000_00); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := Deposit("short", 5_00); err != nil {
+		t.Fatal(err)
+	}
+
+	// A same-runner trade, then lays of b and c that only a cross can take.
+	place(t, Order{MatchID: "three-way", TeamID: "a", UserID: "layer", Side: "ask", Price: 3_00, Quantity: 10_00})
+	place(t, Order{MatchID: "three-way", TeamID: "a", UserID: "punter", Side: "bid", Price: 3_00, Quantity: 10_00})
+	for _, runner := range []string{"b", "c"} {
+		place(t, Order{MatchID: "three-way", TeamID: runner, UserID: "maker", Side: "ask", Price: 3_00, Quantity: 50_00})
+	}
+	mid := TakeSnapshot()
+
+	// Laying a crosses the lays of b and c.
+	if report := place(t, Order{MatchID: "three-way", TeamID: "a", UserID: "layer", Side: "ask", Price: 3_00, Quantity: 5_00}); len(report.Fills) == 0 {
+		t.Fatalf("lay of a did not cross: %+v", report)
+	}
+	if _, err := CashOut("three-way", "punter", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// A GTD back that expires, one that is amended and one that is
+	// cancelled, and a lay its user cannot fund.
+	place(t, Order{ID: "gtd", MatchID: "head-to-head", TeamID: "x", UserID: "punter", Side: "bid", Price: 5_00, Quantity: 20_00,
+		TimeInForce: TimeInForceGTD, ExpiresAt: now().Add(10 * time.Minute)})
+	place(t, Order{ID: "amend", MatchID: "head-to-head", TeamID: "y", UserID: "maker", Side: "bid", Price: 5_00, Quantity: 20_00})
+	if _, err := AmendOrder("head-to-head", "amend", "maker", 4_00, 30_00); err != nil {
+		t.Fatal(err)
+	}
+	place(t, Order{ID: "cancel", MatchID: "head-to-head", TeamID: "x", UserID: "layer", Side: "ask", Price: 2_50, Quantity: 10_00})
+	if _, err := CancelOrder("head-to-head", "cancel", "layer"); err != nil {
+		t.Fatal(err)
+	}
+	if report := place(t, Order{MatchID: "head-to-head", TeamID: "x", UserID: "short", Side: "ask", Price: 3_00, Quantity: 10_00}); report.RejectCode != RejectInsufficientFunds {
+		t.Fatalf("unfunded lay: %+v", report)
+	}
+	advance(15 * time.Minute)
+	if expired := ExpireOrders(now()); len(expired) != 1 || expired[0].ID != "gtd" {
+		t.Fatalf("expired %+v", expired)
+	}
+	if _, err := Withdraw("maker", 10_00); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SettleMatch("three-way", "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := engineState(t)
+	commands := cmdLog.admitted()
+	if err := CloseCommandLog(); err != nil {
+		t.Fatal(err)
+	}
+
+	resetEngine()
+	if n, err := ReplayCommands(path, nil); err != nil || uint64(n) != commands {
+		t.Fatalf("replayed %d of %d commands: %v", n, commands, err)
+	}
+	if got := engineState(t); !bytes.Equal(got, want) {
+		t.Errorf("replay from empty differs:\n got %s\nwant %s", got, want)
+	}
+
+	resetEngine()
+	if err := RestoreSnapshot(mid); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := ReplayCommands(path, nil); err != nil || uint64(n) != commands-mid.CommandSeq {
+		t.Fatalf("replayed %d of %d commands after the snapshot: %v", n, commands-mid.CommandSeq, err)
+	}
+	if got := engineState(t); !bytes.Equal(got, want) {
+		t.Errorf("replay from snapshot differs:\n got %s\nwant %s", got, want)
+	}
+}
+
+// TestOpenCommandLogRefusesGap restores a snapshot taken after the last
+// command a log holds, or with no log at all, and checks that the log is
+// refused and left as it was rather than extended past the gap.
+func TestOpenCommandLogRefusesGap(t *testing.T) {
+	isolateEngine(t)
+	path := filepath.Join(t.TempDir(), "commands.log")
+	if _, err := OpenCommandLog(path); err != nil {
+		t.Fatal(err)
+	}
+	defer CloseCommandLog()
+	if _, err := Deposit("gap", 10_00); err != nil {
+		t.Fatal(err)
+	}
+	lost, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Deposit("gap", 20_00); err != nil {
+		t.Fatal(err)
+	}
+	s := TakeSnapshot()
+	if err := CloseCommandLog(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The second deposit never reached the disk.
+	if err := os.WriteFile(path, lost, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	resetEngine()
+	if err := RestoreSnapshot(s); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenCommandLog(path); !errors.Is(err, ErrCorruptCommandLog) {
+		t.Fatalf("opening a log behind the snapshot: %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || !bytes.Equal(data, lost) {
+		t.Errorf("the refused log was changed: %q, %v", data, err)
+	}
+
+	if _, err := OpenCommandLog(filepath.Join(t.TempDir(), "missing.log")); !errors.Is(err, ErrCorruptCommandLog) {
+		t.Errorf("opening a missing log after a snapshot: %v", err)
+	}
+}
@@ -0,0 +1,244 @@
+package orderbook
+
+import (
+	"log"
+	"math/big"
+)
+
+// Cross matching fills an order against the same side of every other
+// runner's book, because one order per runner covers every outcome:
+//
+//   - Backs cross when their implied probabilities sum to at least 1. The
+//     backers pay their stakes into a pot P and whoever backed the winner
+//     takes it. Backing at p means a stake of at most P/p.
+//   - Lays cross when their implied probabilities sum to at most 1. Each
+//     layer wins W, the backer's stake, when their runner loses and pays the
+//     others' winnings when it wins. Laying at q means that liability is at
+//     most W·(q-1), which holds for every layer exactly when W_k·q_k ≥ ΣW.
+//
+// Every leg is sized at its own odds, rounding in its favour, so nobody
+// trades at a worse price than they asked for. What the winning side could
+// be paid beyond that is the surplus: the legs the market's SurplusPolicy
+// favours are paid all of it, and the house keeps whatever the others leave.
+
+// SurplusPolicy decides who keeps the surplus of a cross match.
+type SurplusPolicy string
+
+const (
+	// SurplusToHouse fills every leg at its own odds; the house keeps the rest.
+	SurplusToHouse SurplusPolicy = "house"
+	// SurplusToTaker improves the incoming order's odds.
+	SurplusToTaker SurplusPolicy = "taker"
+	// SurplusToMaker improves the resting orders' odds.
+	SurplusToMaker SurplusPolicy = "maker"
+)
+
+// DefaultSurplusPolicy applies to markets that never set one.
+const DefaultSurplusPolicy = SurplusToTaker
+
+// Valid reports whether p is one of the known policies.
+func (p SurplusPolicy) Valid() bool {
+	switch p {
+	case SurplusToHouse, SurplusToTaker, SurplusToMaker:
+		return true
+	}
+	return false
+}
+
+// benefits reports whether leg is owed the surplus under p.
+func (p SurplusPolicy) benefits(leg crossLeg) bool {
+	return p == SurplusToTaker && leg.taker || p == SurplusToMaker && !leg.taker
+}
+
+// SetSurplusPolicy changes who keeps the surplus of cross matches in a match.
+func SetSurplusPolicy(matchID string, policy SurplusPolicy) error {
+	if !policy.Valid() {
+		return ErrInvalidSurplusPolicy
+	}
+	m, err := lookupMarket(matchID)
+	if err != nil {
+		return err
+	}
+	cmd := Command{Type: CommandSurplusPolicy, MatchID: m.id, Policy: string(policy)}
+	_, err = marketCommand(m, cmd, func() (struct{}, error) {
+		m.surplusPolicy = policy
+		log.Printf("Match %s: cross-match surplus goes to %s", m.id, policy)
+		return struct{}{}, nil
+	})
+	return err
+}
+
+// crossLeg is one order's part in a cross match.
+type crossLeg struct {
+	order *Order
+	odds  Odds  // the leg trades at these odds or better
+	cap   Money // most of the order's quantity the match may use
+	taker bool
+
+	matched Money // taken off the order's quantity
+	risk    Money // paid if the leg loses
+	win     Money // received if the leg wins
+}
+
+// fairCrossOdds returns the odds at which an order on side would exactly
+// balance makers, resting orders on the other runners. Backs get MaxOdds
+// when the makers alone already cover the book; lays get 0 when no odds
+// could balance it.
+func fairCrossOdds(side string, makers []Odds) Odds {
+	rem := big.NewRat(1, 1)
+	for _, odds := range makers {
+		rem.Sub(rem, big.NewRat(fixedScale, int64(odds)))
+	}
+	if rem.Sign() <= 0 {
+		if side == "bid" {
+			return MaxOdds
+		}
+		return 0
+	}
+
+	// The fair odds are 1/rem; in hundredths that is fixedScale/rem, rounded
+	// towards the side that still crosses.
+	num := new(big.Int).Mul(big.NewInt(fixedScale), rem.Denom())
+	q, r := new(big.Int).QuoRem(num, rem.Num(), new(big.Int))
+	if side == "ask" && r.Sign() != 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if !q.IsInt64() || q.Int64() > int64(MaxOdds) {
+		if side == "bid" {
+			return MaxOdds
+		}
+		return 0
+	}
+	return Odds(q.Int64())
+}
+
+// crossOdds is the odds the order brings to a cross match whose fair odds
+// for it are fair. A market order has no odds of its own and takes the fair
+// odds.
+func (o *Order) crossOdds(fair Odds) Odds {
+	if o.Type == OrderTypeMarket {
+		return fair
+	}
+	return o.Price
+}
+
+// newCrossLegs lines up order against one resting order per other runner.
+// remainingQty is how much of order is left to match.
+func newCrossLegs(order *Order, fair Odds, remainingQty Money, makers []*Order) []crossLeg {
+	legs := []crossLeg{{order: order, odds: order.crossOdds(fair), cap: remainingQty, taker: true}}
+	for _, maker := range makers {
+		legs = append(legs, crossLeg{order: maker, odds: maker.Price, cap: maker.Quantity})
+	}
+	return legs
+}
+
+// sizeCross works out how much each leg of a cross on side trades. It
+// reports false when the legs cannot trade a single paisa each.
+func sizeCross(side string, legs []crossLeg, policy SurplusPolicy) bool {
+	if side == "bid" {
+		return sizeBackCross(legs, policy)
+	}
+	return sizeLayCross(legs, policy)
+}
+
+// sizeBackCross sizes a cross between backs. The pot is the largest, up to
+// what every leg can win at its own odds, that the stakes cover.
+func sizeBackCross(legs []crossLeg, policy SurplusPolicy) bool {
+	pot := legs[0].cap.MulOddsDown(legs[0].odds)
+	for _, leg := range legs[1:] {
+		pot = min(pot, leg.cap.MulOddsDown(leg.odds))
+	}
+
+	// Each leg stakes P/o rounded down. The stakes of a smaller pot never
+	// add up to more, so when a pot P collects S < P, no pot between S and
+	// P is covered either and the search moves straight to S. It ends at
+	// the largest covered pot, or at zero.
+	var staked Money
+	for pot > 0 {
+		staked = 0
+		for i := range legs {
+			legs[i].risk = pot.DivOddsDown(legs[i].odds)
+			staked += legs[i].risk
+		}
+		if staked >= pot {
+			break
+		}
+		pot = staked
+	}
+	if pot <= 0 {
+		return false
+	}
+	for _, leg := range legs {
+		if leg.risk <= 0 {
+			return false
+		}
+	}
+
+	for i := range legs {
+		leg := &legs[i]
+		leg.matched = leg.risk
+		// At its own odds a backer is owed the pot; being paid every stake
+		// collected is the most it can get without the house paying in.
+		leg.win = pot - leg.risk
+		if policy.benefits(*leg) {
+			leg.win = staked - leg.risk
+		}
+	}
+	return true
+}
+
+// sizeLayCross sizes a cross between lays. The book value T is the largest,
+// up to what every leg can lay at its own odds, whose winnings fit inside
+// it, each layer winning W = T/q rounded up.
+func sizeLayCross(legs []crossLeg, policy SurplusPolicy) bool {
+	book := legs[0].cap.MulOddsDown(legs[0].odds)
+	for _, leg := range legs[1:] {
+		book = min(book, leg.cap.MulOddsDown(leg.odds))
+	}
+
+	// While the winnings overflow the book, it moves down to the highest
+	// value at which some layer's W drops by a paisa: every book in between
+	// has the same winnings, so none of them fit either.
+	var won Money
+	for book > 0 {
+		won = 0
+		var next Money
+		for i := range legs {
+			legs[i].win = book.DivOddsUp(legs[i].odds)
+			won += legs[i].win
+			next = max(next, (legs[i].win - 1).MulOddsDown(legs[i].odds))
+		}
+		if won <= book {
+			break
+		}
+		book = next
+	}
+	if book <= 0 {
+		return false
+	}
+
+	for i := range legs {
+		leg := &legs[i]
+		leg.matched = leg.win
+		// At its own odds a layer risks W·(q-1); paying only what the
+		// others win is the least it can risk and still cover them.
+		leg.risk = leg.win.Liability(leg.odds)
+		if policy.benefits(*leg) {
+			leg.risk = won - leg.win
+		}
+		if leg.risk <= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// effectiveOdds is the price a leg of a cross on side actually got: for a
+// back the odds its stake paid, for a lay the odds its liability laid.
+func (leg crossLeg) effectiveOdds(side string) Odds {
+	total := int64(leg.risk + leg.win)
+	if side == "bid" {
+		return Odds(total * fixedScale / int64(leg.risk))
+	}
+	return Odds((total*fixedScale + int64(leg.win) - 1) / int64(leg.win))
+}
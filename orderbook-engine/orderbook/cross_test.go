@@ -0,0 +1,223 @@
+package orderbook
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+var surplusPolicies = []SurplusPolicy{SurplusToHouse, SurplusToTaker, SurplusToMaker}
+
+// crossLegs builds the legs of a cross: the taker first, then one maker
+// per other runner.
+func crossLegs(odds []Odds, caps []Money) []crossLeg {
+	legs := make([]crossLeg, len(odds))
+	for i := range odds {
+		legs[i] = crossLeg{order: &Order{ID: fmt.Sprint(i)}, odds: odds[i], cap: caps[i], taker: i == 0}
+	}
+	return legs
+}
+
+// checkCross checks a sized cross on side: every leg trades within its cap
+// at its own odds or better, whichever runner wins the escrow covers what
+// is paid out, and the legs the policy favours get all of the surplus.
+func checkCross(t *testing.T, side string, legs []crossLeg, policy SurplusPolicy) {
+	t.Helper()
+	var escrow, won Money
+	for _, leg := range legs {
+		escrow += leg.risk
+		won += leg.win
+	}
+	for k, leg := range legs {
+		if leg.matched <= 0 || leg.matched > leg.cap {
+			t.Fatalf("leg %d matched %s of %s", k, leg.matched, leg.cap)
+		}
+		if leg.risk <= 0 || leg.win <= 0 {
+			t.Fatalf("leg %d risks %s to win %s", k, leg.risk, leg.win)
+		}
+
+		// paid is what leaves the escrow when leg k's runner wins.
+		var paid Money
+		if side == "bid" {
+			// A back at o must win at least stake·(o-1).
+			if int64(leg.risk+leg.win)*fixedScale < int64(leg.odds)*int64(leg.risk) {
+				t.Fatalf("back leg %d: %s to win %s is worse than %s", k, leg.risk, leg.win, leg.odds)
+			}
+			paid = leg.risk + leg.win
+			if policy.benefits(leg) && paid != escrow {
+				t.Errorf("back leg %d is owed the surplus: paid %s of %s", k, paid, escrow)
+			}
+		} else {
+			// A lay at q must risk at most W·(q-1).
+			if int64(leg.risk)*fixedScale > int64(leg.win)*int64(leg.odds-fixedScale) {
+				t.Fatalf("lay leg %d: %s to win %s is worse than %s", k, leg.risk, leg.win, leg.odds)
+			}
+			for j, other := range legs {
+				if j != k {
+					paid += other.risk + other.win
+				}
+			}
+			if policy.benefits(leg) && leg.risk != won-leg.win {
+				t.Errorf("lay leg %d is owed the surplus: risks %s to cover %s", k, leg.risk, won-leg.win)
+			}
+		}
+		if paid > escrow {
+			t.Fatalf("if leg %d's runner wins, %s is paid from an escrow of %s", k, paid, escrow)
+		}
+	}
+}
+
+func TestSizeCross(t *testing.T) {
+	tests := []struct {
+		name string
+		side string
+		odds []Odds
+		caps []Money
+	}{
+		{"2 backs", "bid", []Odds{2_00, 2_00}, []Money{10_00, 10_00}},
+		{"2 backs over-round", "bid", []Odds{1_80, 2_20}, []Money{10_00, 7_00}},
+		{"2 lays", "ask", []Odds{2_10, 2_00}, []Money{10_00, 10_00}},
+		{"3 backs", "bid", []Odds{3_00, 3_00, 3_00}, []Money{10_00, 10_00, 10_00}},
+		{"3 backs uneven", "bid", []Odds{2_00, 4_00, 3_50}, []Money{10_00, 4_00, 50_00}},
+		{"3 lays", "ask", []Odds{3_10, 3_00, 3_20}, []Money{10_00, 8_00, 12_00}},
+		{"6 backs", "bid", []Odds{6_00, 5_00, 7_00, 6_40, 6_00, 5_50}, []Money{10_00, 10_00, 10_00, 10_00, 10_00, 10_00}},
+		{"6 lays", "ask", []Odds{7_00, 6_20, 6_60, 7_20, 6_00, 6_40}, []Money{10_00, 3_00, 10_00, 10_00, 10_00, 10_00}},
+		// The sums of 1/odds are within a hair of 1, so no pot near the
+		// caps rounds cleanly: a search one paisa at a time from the top
+		// gave up on these before finding the largest that does.
+		{"3 backs near balance", "bid", []Odds{2_26, 2_94, 4_60}, []Money{20_00, 20_00, 20_00}},
+		{"3 lays near balance", "ask", []Odds{1_56, 230_00, 2_82}, []Money{20_00, 20_00, 20_00}},
+	}
+	for _, tt := range tests {
+		for _, policy := range surplusPolicies {
+			t.Run(tt.name+"/"+string(policy), func(t *testing.T) {
+				legs := crossLegs(tt.odds, tt.caps)
+				if !sizeCross(tt.side, legs, policy) {
+					t.Fatal("cross not sized")
+				}
+				checkCross(t, tt.side, legs, policy)
+				if want := largestCross(tt.side, tt.odds, tt.caps); crossSize(tt.side, legs) != want {
+					t.Errorf("cross of %s, want %s", crossSize(tt.side, legs), want)
+				}
+			})
+		}
+	}
+}
+
+// TestSizeCrossRandom sizes random crosses of two to six runners, with the
+// taker at the fair odds for the makers, and compares each with the
+// largest cross a search of every pot finds.
+func TestSizeCrossRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	ladder := DefaultLadder
+	for range 2000 {
+		side := "bid"
+		if rng.Intn(2) == 1 {
+			side = "ask"
+		}
+		runners := 2 + rng.Intn(5)
+		odds := make([]Odds, runners)
+		caps := make([]Money, runners)
+		for i := 1; i < runners; i++ {
+			odds[i], _ = ladder.At(rng.Intn(120) + (runners-2)*40)
+		}
+		odds[0] = fairCrossOdds(side, odds[1:])
+		if odds[0] <= minOdds {
+			continue
+		}
+		for i := range caps {
+			caps[i] = Money(1 + rng.Intn(50_00))
+		}
+
+		want := largestCross(side, odds, caps)
+		for _, policy := range surplusPolicies {
+			legs := crossLegs(odds, caps)
+			if !sizeCross(side, legs, policy) {
+				if want != 0 {
+					t.Fatalf("%s %v caps %v: not sized, want %s", side, odds, caps, want)
+				}
+				continue
+			}
+			checkCross(t, side, legs, policy)
+			if got := crossSize(side, legs); got != want {
+				t.Fatalf("%s %v caps %v: cross of %s, want %s", side, odds, caps, got, want)
+			}
+		}
+	}
+}
+
+// crossSize is the pot of a sized back cross or the book of a lay cross,
+// as far as the legs show it: the total staked or the total won.
+func crossSize(side string, legs []crossLeg) Money {
+	var total Money
+	for _, leg := range legs {
+		if side == "bid" {
+			total += leg.risk
+		} else {
+			total += leg.win
+		}
+	}
+	return total
+}
+
+// largestCross tries every pot or book from the largest the caps allow
+// down, and returns the total staked or won by the first that works, or 0.
+func largestCross(side string, odds []Odds, caps []Money) Money {
+	top := caps[0].MulOddsDown(odds[0])
+	for i := range odds {
+		top = min(top, caps[i].MulOddsDown(odds[i]))
+	}
+	for size := top; size > 0; size-- {
+		var total Money
+		ok := true
+		for _, o := range odds {
+			var share Money
+			if side == "bid" {
+				share = size.DivOddsDown(o)
+			} else {
+				share = size.DivOddsUp(o)
+			}
+			ok = ok && share > 0
+			total += share
+		}
+		if ok && (side == "bid" && total >= size || side == "ask" && total <= size) {
+			return total
+		}
+	}
+	return 0
+}
+
+// TestCrossMatchLedger runs a four-way cross through the engine under each
+// policy and checks the trade against the ledger: the escrow holds every
+// leg's risk and the journal still sums to zero.
+func TestCrossMatchLedger(t *testing.T) {
+	for _, policy := range surplusPolicies {
+		t.Run(string(policy), func(t *testing.T) {
+			runners := []string{"a", "b", "c", "d"}
+			matchID, user := openTestMarket(t, runners, "u0", "u1", "u2", "u3")
+			if err := SetSurplusPolicy(matchID, policy); err != nil {
+				t.Fatal(err)
+			}
+			for i, runner := range runners[1:] {
+				place(t, Order{MatchID: matchID, TeamID: runner, UserID: user(fmt.Sprint("u", i+1)), Side: "bid", Price: 4_00, Quantity: 10_00})
+			}
+			report := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("u0"), Side: "bid", Price: 3_50, Quantity: 10_00})
+			if len(report.Fills) != 1 || report.Fills[0].MatchType != MatchCrossTeam {
+				t.Fatalf("taker: %+v", report)
+			}
+
+			trades := matchTrades(t, matchID)
+			if len(trades) != 1 {
+				t.Fatalf("%d trades", len(trades))
+			}
+			var escrow Money
+			for _, leg := range trades[0].Legs {
+				escrow += leg.Risk
+			}
+			if got := balanceOf(escrowAccount(matchID)); got != escrow {
+				t.Errorf("escrow holds %s, legs risk %s", got, escrow)
+			}
+			checkLedgerBalances(t)
+		})
+	}
+}
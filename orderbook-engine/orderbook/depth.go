@@ -0,0 +1,121 @@
+package orderbook
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DefaultDepthLevels is how many price levels GetMarketDepth returns per
+// side when no count is given.
+const DefaultDepthLevels = 10
+
+// DepthLevel is every order resting at one price, plus what the other
+// runners' books would cross at that price.
+type DepthLevel struct {
+	Price    Odds  `json:"price"`
+	Quantity Money `json:"quantity"` // backer's stake resting at Price
+	Orders   int   `json:"orders"`
+	Virtual  Money `json:"virtual"` // backer's stake available by cross matching
+}
+
+// MarketDepth is the aggregated book of one runner.
+type MarketDepth struct {
+	MatchID string       `json:"match_id"`
+	TeamID  string       `json:"team_id"`
+	Bids    []DepthLevel `json:"bids"` // backs, lowest odds first: the best a layer can get
+	Asks    []DepthLevel `json:"asks"` // lays, highest odds first: the best a backer can get
+}
+
+// GetMarketDepth returns up to levels price levels on each side of a
+// runner's book, best first. Cross liquidity is folded in as Virtual: a lay
+// of every other runner is a back of this one, and a back of every other
+// runner is a lay of this one.
+func GetMarketDepth(matchID, teamID string, levels int) (MarketDepth, error) {
+	if levels <= 0 {
+		levels = DefaultDepthLevels
+	}
+	m, err := lookupMarket(matchID)
+	if err != nil {
+		return MarketDepth{}, err
+	}
+	return execute(m, func() (MarketDepth, error) {
+		book, ok := m.books[teamID]
+		if !ok {
+			return MarketDepth{}, fmt.Errorf("%w: %q", ErrUnknownRunner, teamID)
+		}
+		return MarketDepth{
+			MatchID: m.id,
+			TeamID:  teamID,
+			Bids:    m.depth(book, teamID, "bid", levels),
+			Asks:    m.depth(book, teamID, "ask", levels),
+		}, nil
+	})
+}
+
+// depth aggregates one side of book with the cross liquidity that takers
+// of that side would also meet.
+func (m *market) depth(book *OrderBook, teamID, side string, levels int) []DepthLevel {
+	byPrice := make(map[Odds]*DepthLevel)
+	level := func(price Odds) *DepthLevel {
+		l, ok := byPrice[price]
+		if !ok {
+			l = &DepthLevel{Price: price}
+			byPrice[price] = l
+		}
+		return l
+	}
+
+	at := now()
+	book.side(side).eachLevel(func(resting *priceLevel) bool {
+		for e := resting.orders.Front(); e != nil; e = e.Next() {
+			if order := e.Value.(*Order); !isExpired(order, at) {
+				l := level(resting.price)
+				l.Quantity += order.Quantity
+				l.Orders++
+			}
+		}
+		return len(byPrice) < levels
+	})
+
+	// Resting backs are hit by lays, which also cross with the other
+	// runners' lays, and vice versa.
+	taker := Order{TeamID: teamID, Side: "ask", Type: OrderTypeMarket}
+	if side == "ask" {
+		taker.Side = "bid"
+	}
+	virtual := make(map[Odds]bool)
+	m.replayCross(taker, MaxMoney, at, func(legs []crossLeg) bool {
+		price, ok := ladderPrice(legs[0].effectiveOdds(taker.Side), taker.Side)
+		if !ok || !virtual[price] && len(virtual) == levels {
+			return false
+		}
+		virtual[price] = true
+		level(price).Virtual += legs[0].matched
+		return true
+	})
+
+	out := make([]DepthLevel, 0, len(byPrice))
+	for _, l := range byPrice {
+		out = append(out, *l)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if side == "bid" {
+			return out[i].Price < out[j].Price
+		}
+		return out[i].Price > out[j].Price
+	})
+	return out[:min(levels, len(out))]
+}
+
+// ladderPrice places a cross price on the ladder without flattering the
+// taker: a back's odds round down, a lay's up.
+func ladderPrice(price Odds, side string) (Odds, bool) {
+	ladder := OddsLadder()
+	if ladder.Valid(price) {
+		return price, true
+	}
+	if side == "bid" {
+		return ladder.SnapDown(min(price, ladder.Max()))
+	}
+	return ladder.SnapUp(price)
+}
@@ -0,0 +1,37 @@
+package orderbook
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestDepthLevels asks for the lays of a at every depth, with lays resting
+// at 2.50 and 2.20 and backs of b that cross as backs of a at 3.00, 2.00
+// and 1.50, and checks that no more levels come back than were asked for.
+func TestDepthLevels(t *testing.T) {
+	matchID, user := openTestMarket(t, []string{"a", "b"}, "layer", "backer")
+	for _, price := range []Odds{2_50, 2_20} {
+		place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("layer"), Side: "ask", Price: price, Quantity: 10_00})
+	}
+	for _, price := range []Odds{1_50, 2_00, 3_00} {
+		place(t, Order{MatchID: matchID, TeamID: "b", UserID: user("backer"), Side: "bid", Price: price, Quantity: 10_00})
+	}
+
+	all := []Odds{3_00, 2_50, 2_20, 2_00, 1_50}
+	for levels := 1; levels <= len(all)+1; levels++ {
+		depth, err := GetMarketDepth(matchID, "a", levels)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var prices []Odds
+		for _, l := range depth.Asks {
+			if l.Quantity+l.Virtual <= 0 {
+				t.Errorf("%d levels: empty level at %s", levels, l.Price)
+			}
+			prices = append(prices, l.Price)
+		}
+		if want := all[:min(levels, len(all))]; !slices.Equal(prices, want) {
+			t.Errorf("%d levels: asks at %v, want %v", levels, prices, want)
+		}
+	}
+}
@@ -3,153 +3,352 @@ package orderbook
 import (
 	"fmt"
 	"log"
-	"sync"
+	"strconv"
+	"strings"
+	"sync/atomic"
 )
 
+// OrderBook holds one runner's resting orders. Bids back the runner and
+// asks lay it; both are priced in decimal odds and sized by the backer's
+// stake.
 type OrderBook struct {
-	Bids *Heap[Order] // Max-heap for Bids
-	Asks *Heap[Order] // Min-heap for Asks
-	mu   sync.Mutex
+	Bids   *PriceLevels      // Backs, lowest odds first
+	Asks   *PriceLevels      // Lays, highest odds first
+	orders map[string]*Order // orderID → resting order
 }
 
-// --- Storage for Match Data ---
+func newOrderBook() *OrderBook {
+	return &OrderBook{
+		Bids:   newPriceLevels(bidPriority),
+		Asks:   newPriceLevels(askPriority),
+		orders: make(map[string]*Order),
+	}
+}
 
-var (
-	MatchBooks = make(map[string]map[string]*OrderBook) // matchID → (teamID → OrderBook)
-	MatchTeams = make(map[string][2]string)             // matchID → [teamA, teamB]
-	mu         sync.RWMutex
-)
+// rest adds an order to its side of the book and indexes it by ID.
+func (b *OrderBook) rest(order *Order) {
+	if order.Side == "bid" {
+		b.Bids.Push(order)
+	} else {
+		b.Asks.Push(order)
+	}
+	b.orders[order.ID] = order
+}
 
-// --- Match Registration ---
+// side returns the price levels holding orders on side.
+func (b *OrderBook) side(side string) *PriceLevels {
+	if side == "bid" {
+		return b.Bids
+	}
+	return b.Asks
+}
 
-func RegisterMatch(matchID, teamA, teamB string) {
-	mu.Lock()
-	defer mu.Unlock()
-	MatchTeams[matchID] = [2]string{teamA, teamB}
-	MatchBooks[matchID] = make(map[string]*OrderBook)
+// remove takes a resting order out of the book.
+func (b *OrderBook) remove(order *Order) {
+	if order.Side == "bid" {
+		b.Bids.Remove(order)
+	} else {
+		b.Asks.Remove(order)
+	}
+	delete(b.orders, order.ID)
 }
 
-// --- OrderBook Retrieval ---
+// --- Price-Time Priority ---
 
-func getOrderBook(matchID, teamID string) *OrderBook {
-	mu.Lock()
-	defer mu.Unlock()
+// Orders at the same price queue in the order they were accepted, so only
+// the price levels need ranking.
 
-	if _, exists := MatchBooks[matchID]; !exists {
-		MatchBooks[matchID] = make(map[string]*OrderBook)
-	}
-	if _, exists := MatchBooks[matchID][teamID]; !exists {
-		MatchBooks[matchID][teamID] = &OrderBook{
-			Bids: New[Order](func(a, b Order) bool { return a.Price > b.Price }),
-			Asks: New[Order](func(a, b Order) bool { return a.Price < b.Price }),
-		}
-	}
-	return MatchBooks[matchID][teamID]
+// bidPriority ranks backs by lowest odds first, the best price for a layer.
+func bidPriority(a, b Odds) bool {
+	return a < b
 }
 
-// --- Opposing Team Lookup ---
+// askPriority ranks lays by highest odds first, the best price for a backer.
+func askPriority(a, b Odds) bool {
+	return a > b
+}
 
-func getOpposingTeamID(matchID, teamID string) string {
-	mu.RLock()
-	defer mu.RUnlock()
-	teams := MatchTeams[matchID]
-	if teams[0] == teamID {
-		return teams[1]
+// --- Enhanced Place Order with Sports Betting Logic ---
+
+// PlaceOrder queues the order on its market, waits for it to be matched and
+// returns what happened to it.
+func PlaceOrder(order Order) (ExecutionReport, error) {
+	m, err := lookupMarket(order.MatchID)
+	if err != nil {
+		return rejectOrder(order, RejectUnknownMatch, "match is not registered"), nil
 	}
-	return teams[0]
+	return marketCommand(m, Command{Type: CommandPlaceOrder, MatchID: m.id, Order: &order}, func() (ExecutionReport, error) {
+		return m.placeOrder(order), nil
+	})
 }
 
-// --- Enhanced Place Order with Sports Betting Logic ---
+func (m *market) placeOrder(order Order) ExecutionReport {
+	// Stamp the order with its command so resting orders at the same
+	// price are filled first-come-first-served.
+	order.Seq = m.cmd.seq
+	if order.ID == "" {
+		order.ID = strconv.FormatUint(order.Seq, 10)
+	} else if isDigits(order.ID) {
+		// Numeric IDs are the engine's own, so a client ID can never
+		// collide with one it assigns later.
+		return rejectOrder(order, RejectReservedOrderID, "numeric order ids are reserved for ids the engine assigns")
+	}
+	if order.Type == "" {
+		order.Type = OrderTypeLimit
+	}
+	if order.TimeInForce == "" {
+		order.TimeInForce = TimeInForceGTC
+	}
+	if order.TimeInForce == TimeInForceGTD && order.ExpiresAt.IsZero() {
+		order.ExpiresAt = m.start
+	}
+
+	if code, reason := m.validateOrder(&order); code != "" {
+		return rejectOrder(order, code, reason)
+	}
+	m.orderIDs[order.ID] = struct{}{}
+	if order.Type == OrderTypeMarket && order.TimeInForce != TimeInForceFOK {
+		// Market orders never rest: whatever cannot be filled is discarded.
+		order.TimeInForce = TimeInForceIOC
+	}
+	if isExpired(&order, m.cmd.at) {
+		reportExpired(order)
+		return ExecutionReport{OrderID: order.ID, Status: StatusExpired}
+	}
+
+	book := m.books[order.TeamID]
+
+	if order.TimeInForce == TimeInForceFOK &&
+		m.fillableQuantity(order, book) < order.Quantity {
+		log.Printf("FOK order %s killed: cannot fill %s units at %s",
+			order.ID, order.Quantity, order.Price)
+		return ExecutionReport{OrderID: order.ID, Status: StatusCancelled}
+	}
+	if err := m.reserve(&order); err != nil {
+		return rejectOrder(order, rejectCodeFor(err), err.Error())
+	}
 
-func PlaceOrder(order Order) {
-	book := getOrderBook(order.MatchID, order.TeamID)
-	opposingTeamID := getOpposingTeamID(order.MatchID, order.TeamID)
-	opposingBook := getOrderBook(order.MatchID, opposingTeamID)
+	report := m.matchAndRest(&order, book)
+	m.release(&order)
 
-	book.mu.Lock()
-	opposingBook.mu.Lock()
-	defer book.mu.Unlock()
-	defer opposingBook.mu.Unlock()
+	// Update market prices after matching
+	m.updateMatchPrices()
+
+	// Print the updated orderbook state when debugging
+	if printBooks.Load() {
+		PrintOrderBook(m.id, m.runners, m.books)
+	}
+
+	// Publish the event for other services
+	PublishMatchEvent(order)
+
+	return report
+}
+
+// matchAndRest runs an order against its own team's book and then across
+// the other runners, and rests whatever is left if its time in force
+// allows. Must run on the market goroutine.
+func (m *market) matchAndRest(order *Order, book *OrderBook) ExecutionReport {
+	report := ExecutionReport{OrderID: order.ID}
+	quantity := order.Quantity
+	remainingQty := order.Quantity
 
 	if order.Side == "bid" {
-		// When someone wants to buy Team A shares (BACK Team A)
-		remainingQty := order.Quantity
-		
-		// STRATEGY 1: Match with asks (sell orders) in the same team's orderbook
-		// This represents someone who wants to SELL their Team A position
-		remainingQty = matchWithSameTeamAsks(order, book, remainingQty)
-		
-		// STRATEGY 2: Cross-team matching with opposing team's bids
-		// Buying Team A is equivalent to selling Team B
-		// So we can match with people buying Team B at compatible odds
-		if remainingQty > 0 {
-			remainingQty = matchWithOpposingTeamBids(order, opposingBook, remainingQty, opposingTeamID)
+		// When someone wants to BACK Team A
+
+		// STRATEGY 1: Match with asks (lays) in the same team's orderbook
+		// at the layer's odds, as long as they are at least ours
+		remainingQty = m.matchWithSameTeamAsks(*order, book, remainingQty, &report)
+
+		// STRATEGY 2: Cross matching with bids on every other runner
+		// A back on each runner together covers every outcome, so they
+		// can fund a single pot between them
+		if remainingQty > 0 && !report.incomingCancelled {
+			remainingQty = m.matchWithOtherRunners(*order, remainingQty, &report)
 		}
 
 		// If we still have quantity to fill, add to orderbook
-		if remainingQty > 0 {
+		if remainingQty > 0 && order.rests() {
 			order.Quantity = remainingQty
-			book.Bids.Push(order)
-			log.Printf("Partial fill - Added remaining bid to orderbook: %.2f units at %.2f", 
+			report.Resting = remainingQty
+			book.rest(order)
+			log.Printf("Partial fill - Added remaining bid to orderbook: %s units at %s",
 				remainingQty, order.Price)
 		}
 
 	} else if order.Side == "ask" {
-		// When someone wants to sell Team A shares (LAY Team A)
-		remainingQty := order.Quantity
-		
-		// STRATEGY 1: Match with bids (buy orders) in the same team's orderbook
-		// This represents someone who wants to BUY Team A position
-		remainingQty = matchWithSameTeamBids(order, book, remainingQty)
-		
-		// STRATEGY 2: Cross-team matching with opposing team's asks
-		// Selling Team A is equivalent to buying Team B
-		// So we can match with people selling Team B at compatible odds
-		if remainingQty > 0 {
-			remainingQty = matchWithOpposingTeamAsks(order, opposingBook, remainingQty, opposingTeamID)
+		// When someone wants to LAY Team A
+		// STRATEGY 1: Match with bids (backs) in the same team's orderbook
+		// at the backer's odds, as long as they are at most ours
+		remainingQty = m.matchWithSameTeamBids(*order, book, remainingQty, &report)
+
+		// STRATEGY 2: Cross matching with asks on every other runner
+		// A lay of each runner pays out on the others, so their
+		// liabilities can cover each other's winnings
+		if remainingQty > 0 && !report.incomingCancelled {
+			remainingQty = m.matchWithOtherRunners(*order, remainingQty, &report)
 		}
 
 		// If we still have quantity to fill, add to orderbook
-		if remainingQty > 0 {
+		if remainingQty > 0 && order.rests() {
 			order.Quantity = remainingQty
-			book.Asks.Push(order)
-			log.Printf("Partial fill - Added remaining ask to orderbook: %.2f units at %.2f", 
+			report.Resting = remainingQty
+			book.rest(order)
+			log.Printf("Partial fill - Added remaining ask to orderbook: %s units at %s",
 				remainingQty, order.Price)
 		}
 	}
 
-	// Update market prices after matching
-	updateMatchPrices(order.MatchID)
-	
-	// Print the updated orderbook state
-	PrintOrderBook(order.MatchID, order.TeamID, book, opposingTeamID, opposingBook)
-	
-	// Publish the event for other services
-	PublishMatchEvent(order)
+	if report.incomingCancelled {
+		log.Printf("Order %s %s", order.ID, report.Reason)
+	} else if remainingQty > 0 && !order.rests() {
+		log.Printf("%s order %s: discarded unfilled %s units", order.TimeInForce, order.ID, remainingQty)
+	}
+	report.finish(quantity)
+	return report
+}
+
+// --- Cancel Order ---
+
+// CancelOrder removes a resting order from the book and returns its
+// unfilled quantity.
+func CancelOrder(matchID, orderID, userID string) (Money, error) {
+	m, err := lookupMarket(matchID)
+	if err != nil {
+		return 0, ErrOrderNotFound
+	}
+	cmd := Command{Type: CommandCancelOrder, MatchID: m.id, OrderID: orderID, UserID: userID}
+	return marketCommand(m, cmd, func() (Money, error) {
+		return m.cancelOrder(orderID, userID)
+	})
+}
+
+func (m *market) cancelOrder(orderID, userID string) (Money, error) {
+	book, order := m.findOrder(orderID)
+	if order == nil {
+		return 0, ErrOrderNotFound
+	}
+	if order.UserID != userID {
+		return 0, ErrNotOrderOwner
+	}
+	book.remove(order)
+	m.release(order)
+
+	log.Printf("Order %s cancelled by %s: %s units at %s unfilled",
+		orderID, userID, order.Quantity, order.Price)
+	return order.Quantity, nil
+}
+
+// --- Amend Order ---
+
+// AmendOrder changes the price and/or quantity of a resting order. Reducing
+// the quantity at the same price keeps the order's queue position; any other
+// change re-queues it and matches it again like a new order. A GTD order
+// past its expiry is expired instead, with ErrOrderExpired.
+func AmendOrder(matchID, orderID, userID string, price Odds, quantity Money) (Order, error) {
+	if price <= 0 || quantity <= 0 {
+		return Order{}, ErrInvalidAmendment
+	}
+
+	m, err := lookupMarket(matchID)
+	if err != nil {
+		return Order{}, ErrOrderNotFound
+	}
+	cmd := Command{Type: CommandAmendOrder, MatchID: m.id, OrderID: orderID, UserID: userID, Price: price, Quantity: quantity}
+	return marketCommand(m, cmd, func() (Order, error) {
+		return m.amendOrder(orderID, userID, price, quantity)
+	})
+}
+
+func (m *market) amendOrder(orderID, userID string, price Odds, quantity Money) (Order, error) {
+	book, order := m.findOrder(orderID)
+	if order == nil {
+		return Order{}, ErrOrderNotFound
+	}
+	if order.UserID != userID {
+		return Order{}, ErrNotOrderOwner
+	}
+	if isExpired(order, m.cmd.at) {
+		// The sweeper has not reached it yet; amending must not revive it.
+		m.expireOrder(book, order)
+		return Order{}, ErrOrderExpired
+	}
+	if !m.state.acceptsOrders() {
+		return Order{}, fmt.Errorf("%w: market is %s", ErrMarketNotOpen, m.state)
+	}
+	price, err := alignToLadder(price, order.Side)
+	if err != nil {
+		return Order{}, err
+	}
+
+	if price == order.Price && quantity <= order.Quantity {
+		// Size reduction only: the order keeps its place in the queue.
+		log.Printf("Order %s reduced in place: %s -> %s units at %s",
+			orderID, order.Quantity, quantity, price)
+		order.Quantity = quantity
+		m.release(order)
+		PublishMatchEvent(*order)
+		return *order, nil
+	}
+
+	// Price change or size increase: cancel and replace with a fresh
+	// sequence, once the new terms are funded.
+	replacement := *order
+	replacement.Price = price
+	replacement.Quantity = quantity
+	if err := m.reserve(&replacement); err != nil {
+		return Order{}, err
+	}
+	book.remove(order)
+	order.Price = price
+	order.Quantity = quantity
+	order.Seq = m.cmd.seq
+	log.Printf("Order %s re-queued: %s units at %s", orderID, quantity, price)
+
+	amended := *order
+	report := m.matchAndRest(order, book)
+	m.release(order)
+	amended.Quantity = report.Resting
+
+	m.updateMatchPrices()
+	PublishMatchEvent(amended)
+	return amended, nil
 }
 
 // --- Matching Functions ---
 
-func matchWithSameTeamAsks(bidOrder Order, book *OrderBook, remainingQty float64) float64 {
+func (m *market) matchWithSameTeamAsks(bidOrder Order, book *OrderBook, remainingQty Money, report *ExecutionReport) Money {
 	for remainingQty > 0 && book.Asks.Len() > 0 {
 		bestAsk := book.Asks.Peek()
-		
-		// Check if the ask price is less than or equal to bid price
-		if bestAsk.Price > bidOrder.Price {
+		if isExpired(bestAsk, m.cmd.at) {
+			m.expireOrder(book, bestAsk)
+			continue
+		}
+
+		// Check if the lay odds are at least the odds we asked for
+		if !bidOrder.acceptsPrice(bestAsk.Price) {
 			break // No matching asks at acceptable price
 		}
 
+		if bestAsk.UserID == bidOrder.UserID {
+			qty := min(remainingQty, bestAsk.Quantity)
+			remainingQty = m.preventSelfTrade(bidOrder, bestAsk, book, qty, qty, remainingQty, report)
+			continue
+		}
+
 		// Calculate how much we can trade
 		matchQty := min(remainingQty, bestAsk.Quantity)
 		tradePrice := bestAsk.Price // Use ask price for the trade
-		tradeValue := tradePrice * matchQty
 
 		// Execute the trade
-		log.Printf("SAME-TEAM Match: Bid for %s - %.2f units at Price %.2f (Total: ₹%.2f)", 
-			bidOrder.TeamID, matchQty, tradePrice, tradeValue)
+		log.Printf("SAME-TEAM Match: Bid for %s - %s units at Price %s",
+			bidOrder.TeamID, matchQty, tradePrice)
 
-		// TODO: Transfer money and shares
-		executeTrade(bidOrder.UserID, bestAsk.UserID, matchQty, tradePrice, bidOrder.TeamID, "SAME_TEAM_BID_ASK")
+		if err := m.executeTrade(&bidOrder, bestAsk, matchQty, tradePrice, "SAME_TEAM_BID_ASK"); err != nil {
+			report.rejectFill(err)
+			return 0
+		}
+		report.Fills = append(report.Fills, Fill{CounterpartyOrderID: bestAsk.ID, Price: tradePrice, Quantity: matchQty, MatchType: MatchSameTeam})
 
 		// Update quantities
 		bestAsk.Quantity -= matchQty
@@ -157,32 +356,51 @@ func matchWithSameTeamAsks(bidOrder Order, book *OrderBook, remainingQty float64
 
 		// Remove the ask if fully filled
 		if bestAsk.Quantity <= 0 {
-			book.Asks.Pop()
+			book.remove(bestAsk)
+			m.release(bestAsk)
 		}
 	}
 	return remainingQty
 }
 
-func matchWithSameTeamBids(askOrder Order, book *OrderBook, remainingQty float64) float64 {
+func (m *market) matchWithSameTeamBids(askOrder Order, book *OrderBook, remainingQty Money, report *ExecutionReport) Money {
 	for remainingQty > 0 && book.Bids.Len() > 0 {
 		bestBid := book.Bids.Peek()
-		
-		// Check if the bid price is greater than or equal to ask price
-		if bestBid.Price < askOrder.Price {
+		if isExpired(bestBid, m.cmd.at) {
+			m.expireOrder(book, bestBid)
+			continue
+		}
+
+		// Check if the back odds are at most the odds we will lay
+		if !askOrder.acceptsPrice(bestBid.Price) {
 			break // No matching bids at acceptable price
 		}
 
+		if bestBid.UserID == askOrder.UserID {
+			qty := min(remainingQty, bestBid.Quantity)
+			remainingQty = m.preventSelfTrade(askOrder, bestBid, book, qty, qty, remainingQty, report)
+			continue
+		}
+
 		// Calculate how much we can trade
 		matchQty := min(remainingQty, bestBid.Quantity)
 		tradePrice := bestBid.Price // Use bid price for the trade
-		tradeValue := tradePrice * matchQty
 
 		// Execute the trade
-		log.Printf("SAME-TEAM Match: Ask for %s - %.2f units at Price %.2f (Total: ₹%.2f)", 
-			askOrder.TeamID, matchQty, tradePrice, tradeValue)
+		log.Printf("SAME-TEAM Match: Ask for %s - %s units at Price %s",
+			askOrder.TeamID, matchQty, tradePrice)
 
-		// TODO: Transfer money and shares
-		executeTrade(bestBid.UserID, askOrder.UserID, matchQty, tradePrice, askOrder.TeamID, "SAME_TEAM_ASK_BID")
+		if err := m.executeTrade(bestBid, &askOrder, matchQty, tradePrice, "SAME_TEAM_ASK_BID"); err != nil {
+			report.rejectFill(err)
+			return 0
+		}
+		report.Fills = append(report.Fills, Fill{
+			CounterpartyOrderID: bestBid.ID,
+			Price:               tradePrice,
+			Quantity:            matchQty,
+			Liability:           matchQty.Liability(tradePrice),
+			MatchType:           MatchSameTeam,
+		})
 
 		// Update quantities
 		bestBid.Quantity -= matchQty
@@ -190,203 +408,206 @@ func matchWithSameTeamBids(askOrder Order, book *OrderBook, remainingQty float64
 
 		// Remove the bid if fully filled
 		if bestBid.Quantity <= 0 {
-			book.Bids.Pop()
+			book.remove(bestBid)
+			m.release(bestBid)
 		}
 	}
 	return remainingQty
 }
 
-func matchWithOpposingTeamBids(bidOrder Order, opposingBook *OrderBook, remainingQty float64, opposingTeamID string) float64 {
-	// Buying Team A can match with buying Team B if the combined odds make sense
-	// This creates natural price discovery between teams
-	
-	for remainingQty > 0 && opposingBook.Bids.Len() > 0 {
-		opposingBid := opposingBook.Bids.Peek()
-		
-		// Check if cross-team trade is profitable
-		// The sum of odds should be close to the total probability (accounting for margin)
-		if !areOddsCompatibleForCrossTrade(bidOrder.Price, opposingBid.Price) {
+// matchWithOtherRunners cross matches order against the best order on the
+// same side of every other runner's book, for as long as they cross.
+func (m *market) matchWithOtherRunners(order Order, remainingQty Money, report *ExecutionReport) Money {
+	for remainingQty > 0 {
+		makers, books := m.bestCrossMakers(order)
+		if makers == nil {
+			break // some runner has nothing to cross with
+		}
+		fair := fairCrossOdds(order.Side, makerOdds(makers))
+		if fair == 0 || !order.acceptsPrice(fair) {
 			break
 		}
 
-		matchQty := min(remainingQty, opposingBid.Quantity)
-		// Use average price or more sophisticated pricing model
-		tradePrice := calculateCrossTradePrice(bidOrder.Price, opposingBid.Price)
-		tradeValue := tradePrice * matchQty
-
-		log.Printf("CROSS-TEAM Match: %s Bid %.2f vs %s Bid %.2f - %.2f units at %.2f (Total: ₹%.2f)", 
-			bidOrder.TeamID, bidOrder.Price, opposingTeamID, opposingBid.Price, 
-			matchQty, tradePrice, tradeValue)
-
-		// Execute cross-team trade
-		executeCrossTrade(bidOrder.UserID, opposingBid.UserID, matchQty, tradePrice, 
-			bidOrder.TeamID, opposingTeamID, "CROSS_TEAM_BID_BID")
-
-		opposingBid.Quantity -= matchQty
-		remainingQty -= matchQty
+		legs := newCrossLegs(&order, fair, remainingQty, makers)
+		if !sizeCross(order.Side, legs, m.surplusPolicy) {
+			break
+		}
+		if i := selfTradeMaker(order, makers); i >= 0 {
+			// The legs differ in size, so each order gives up what its own
+			// leg of the cross would have traded.
+			remainingQty = m.preventSelfTrade(order, makers[i], books[i], legs[0].matched, legs[i+1].matched, remainingQty, report)
+			continue
+		}
+		if err := m.executeCrossTrade(order.Side, legs); err != nil {
+			report.rejectFill(err)
+			return 0
+		}
 
-		if opposingBid.Quantity <= 0 {
-			opposingBook.Bids.Pop()
+		taker := legs[0]
+		fill := Fill{
+			CounterpartyOrderID:  makers[0].ID,
+			CounterpartyOrderIDs: make([]string, 0, len(makers)),
+			Price:                taker.effectiveOdds(order.Side),
+			Quantity:             taker.matched,
+			MatchType:            MatchCrossTeam,
+		}
+		if order.Side == "ask" {
+			fill.Liability = taker.risk
+		}
+		for i, maker := range makers {
+			fill.CounterpartyOrderIDs = append(fill.CounterpartyOrderIDs, maker.ID)
+			maker.Quantity -= legs[i+1].matched
+			if maker.Quantity <= 0 {
+				books[i].remove(maker)
+				m.release(maker)
+			}
 		}
+		report.Fills = append(report.Fills, fill)
+		remainingQty -= taker.matched
 	}
 	return remainingQty
 }
 
-func matchWithOpposingTeamAsks(askOrder Order, opposingBook *OrderBook, remainingQty float64, opposingTeamID string) float64 {
-	// Selling Team A can match with selling Team B under certain conditions
-	
-	for remainingQty > 0 && opposingBook.Asks.Len() > 0 {
-		opposingAsk := opposingBook.Asks.Peek()
-		
-		if !areOddsCompatibleForCrossTrade(askOrder.Price, opposingAsk.Price) {
-			break
+// bestCrossMakers returns the best live order on the order's side of every
+// other runner's book, and those books, or nil if any of them is empty.
+func (m *market) bestCrossMakers(order Order) ([]*Order, []*OrderBook) {
+	others := m.otherRunners(order.TeamID)
+	makers := make([]*Order, 0, len(others))
+	books := make([]*OrderBook, 0, len(others))
+	for _, runner := range others {
+		book := m.books[runner]
+		orders := book.side(order.Side)
+		for orders.Len() > 0 && isExpired(orders.Peek(), m.cmd.at) {
+			m.expireOrder(book, orders.Peek())
 		}
+		if orders.Len() == 0 {
+			return nil, nil
+		}
+		makers = append(makers, orders.Peek())
+		books = append(books, book)
+	}
+	return makers, books
+}
 
-		matchQty := min(remainingQty, opposingAsk.Quantity)
-		tradePrice := calculateCrossTradePrice(askOrder.Price, opposingAsk.Price)
-		tradeValue := tradePrice * matchQty
-
-		log.Printf("CROSS-TEAM Match: %s Ask %.2f vs %s Ask %.2f - %.2f units at %.2f (Total: ₹%.2f)", 
-			askOrder.TeamID, askOrder.Price, opposingTeamID, opposingAsk.Price, 
-			matchQty, tradePrice, tradeValue)
-
-		executeCrossTrade(askOrder.UserID, opposingAsk.UserID, matchQty, tradePrice, 
-			askOrder.TeamID, opposingTeamID, "CROSS_TEAM_ASK_ASK")
-
-		opposingAsk.Quantity -= matchQty
-		remainingQty -= matchQty
+func makerOdds(makers []*Order) []Odds {
+	odds := make([]Odds, len(makers))
+	for i, maker := range makers {
+		odds[i] = maker.Price
+	}
+	return odds
+}
 
-		if opposingAsk.Quantity <= 0 {
-			opposingBook.Asks.Pop()
+// selfTradeMaker returns the index of the first maker owned by the order's
+// user, or -1.
+func selfTradeMaker(order Order, makers []*Order) int {
+	for i, maker := range makers {
+		if maker.UserID == order.UserID {
+			return i
 		}
 	}
-	return remainingQty
+	return -1
 }
 
 // --- Trading and Price Logic ---
 
-func executeTrade(buyerID, sellerID string, quantity, price float64, teamID, tradeType string) {
-	tradeValue := price * quantity
-	log.Printf("TRADE EXECUTED [%s]: Buyer: %s, Seller: %s, Team: %s, Qty: %.2f, Price: %.2f, Value: ₹%.2f", 
-		tradeType, buyerID, sellerID, teamID, quantity, price, tradeValue)
-	
-	// TODO: Implement actual transfers
-	// transferMoney(buyerID, sellerID, tradeValue)
-	// transferShares(sellerID, buyerID, quantity, teamID)
+func (m *market) executeTrade(backer, layer *Order, stake Money, price Odds, tradeType string) error {
+	liability := stake.Liability(price)
+	log.Printf("TRADE EXECUTED [%s]: Backer: %s, Layer: %s, Team: %s, Stake: %s, Price: %s, Liability: ₹%s",
+		tradeType, backer.UserID, layer.UserID, backer.TeamID, stake, price, liability)
+
+	// The backer's stake and the layer's liability are held in escrow until settlement
+	_, err := m.recordTrade(MatchSameTeam, []TradeLeg{
+		{OrderID: backer.ID, UserID: backer.UserID, TeamID: backer.TeamID, Side: "bid", Odds: price, Risk: stake, Win: liability},
+		{OrderID: layer.ID, UserID: layer.UserID, TeamID: layer.TeamID, Side: "ask", Odds: price, Risk: liability, Win: stake},
+	})
+	return err
 }
 
-func executeCrossTrade(user1ID, user2ID string, quantity, price float64, team1ID, team2ID, tradeType string) {
-	tradeValue := price * quantity
-	log.Printf("CROSS-TRADE EXECUTED [%s]: User1: %s (%s), User2: %s (%s), Qty: %.2f, Price: %.2f, Value: ₹%.2f", 
-		tradeType, user1ID, team1ID, user2ID, team2ID, quantity, price, tradeValue)
-	
-	// TODO: Implement cross-team position transfers
-	// This is more complex as it involves offsetting positions
+func (m *market) executeCrossTrade(side string, legs []crossLeg) error {
+	tradeLegs := make([]TradeLeg, 0, len(legs))
+	for _, leg := range legs {
+		odds := leg.effectiveOdds(side)
+		log.Printf("CROSS-TRADE EXECUTED [%s]: User: %s, Team: %s, Odds: %s, Risk: ₹%s, Win: ₹%s",
+			side, leg.order.UserID, leg.order.TeamID, odds, leg.risk, leg.win)
+		tradeLegs = append(tradeLegs, TradeLeg{
+			OrderID: leg.order.ID,
+			UserID:  leg.order.UserID,
+			TeamID:  leg.order.TeamID,
+			Side:    side,
+			Odds:    odds,
+			Risk:    leg.risk,
+			Win:     leg.win,
+		})
+	}
+	// Every leg's risk goes into one escrow; the winning legs are paid from it
+	_, err := m.recordTrade(MatchCrossTeam, tradeLegs)
+	return err
 }
 
-func areOddsCompatibleForCrossTrade(odds1, odds2 float64) bool {
-	// Check if the combined implied probabilities make sense for a cross-trade
-	impliedProb1 := 1.0 / odds1
-	impliedProb2 := 1.0 / odds2
-	totalProb := impliedProb1 + impliedProb2
-	
-	// Allow trades where total probability is between 95% and 105% (5% margin)
-	return totalProb >= 0.95 && totalProb <= 1.05
-}
+func (m *market) updateMatchPrices() {
+	// Calculate current market prices based on best bids/asks
+	prices := make([]string, 0, len(m.runners))
+	for _, runner := range m.runners {
+		prices = append(prices, fmt.Sprintf("%s=%s", runner, calculateMarketPrice(m.books[runner])))
+	}
 
-func calculateCrossTradePrice(odds1, odds2 float64) float64 {
-	// Simple average - you can implement more sophisticated pricing
-	return (odds1 + odds2) / 2
+	log.Printf("Market Update - Match %s: %s", m.id, strings.Join(prices, ", "))
 }
 
-func updateMatchPrices(matchID string) {
-	mu.RLock()
-	defer mu.RUnlock()
-	
-	teams := MatchTeams[matchID]
-	teamABook := MatchBooks[matchID][teams[0]]
-	teamBBook := MatchBooks[matchID][teams[1]]
-	
-	if teamABook == nil || teamBBook == nil {
-		return
-	}
-	
-	// Calculate current market prices based on best bids/asks
-	teamAPrice := calculateMarketPrice(teamABook)
-	teamBPrice := calculateMarketPrice(teamBBook)
-	
-	log.Printf("Market Update - Match %s: %s=%.2f, %s=%.2f", 
-		matchID, teams[0], teamAPrice, teams[1], teamBPrice)
-}
+func calculateMarketPrice(book *OrderBook) Odds {
+	var midPrice Odds = 2_00 // Default odds
 
-func calculateMarketPrice(book *OrderBook) float64 {
-	book.mu.Lock()
-	defer book.mu.Unlock()
-	
-	var midPrice float64 = 2.0 // Default odds
-	
 	if book.Bids.Len() > 0 && book.Asks.Len() > 0 {
 		bestBid := book.Bids.Peek().Price
 		bestAsk := book.Asks.Peek().Price
-		midPrice = (bestBid + bestAsk) / 2
+		midPrice = Odds(divRound(int64(bestBid+bestAsk), 2))
 	} else if book.Bids.Len() > 0 {
 		midPrice = book.Bids.Peek().Price
 	} else if book.Asks.Len() > 0 {
 		midPrice = book.Asks.Peek().Price
 	}
-	
+
 	return midPrice
 }
 
 // --- Helper Functions ---
 
-func min(a, b float64) float64 {
-	if a < b {
-		return a
-	}
-	return b
+// printBooks turns on printing every book after each order, for debugging.
+// It walks every level, so it is off by default.
+var printBooks atomic.Bool
+
+// SetPrintBooks turns printing the books after each order on or off.
+func SetPrintBooks(on bool) {
+	printBooks.Store(on)
 }
 
-func PrintOrderBook(matchID, teamID string, book *OrderBook, opposingTeamID string, opposingBook *OrderBook) {
+func PrintOrderBook(matchID string, runners []string, books map[string]*OrderBook) {
 	fmt.Printf("\n=== Match %s Order Books ===\n", matchID)
-	
-	fmt.Printf("Team %s:\n", teamID)
-	fmt.Println("  Bids (Buy Orders):")
-	if book.Bids.Len() > 0 {
-		for _, bid := range book.Bids.Items() {
-			fmt.Printf("    Price: %.2f, Quantity: %.2f, User: %s\n", bid.Price, bid.Quantity, bid.UserID)
+
+	for i, runner := range runners {
+		book := books[runner]
+		if i > 0 {
+			fmt.Println()
 		}
-	} else {
-		fmt.Println("    No bids")
-	}
-	
-	fmt.Println("  Asks (Sell Orders):")
-	if book.Asks.Len() > 0 {
-		for _, ask := range book.Asks.Items() {
-			fmt.Printf("    Price: %.2f, Quantity: %.2f, User: %s\n", ask.Price, ask.Quantity, ask.UserID)
+		fmt.Printf("Team %s:\n", runner)
+		fmt.Println("  Bids (Backs):")
+		if book.Bids.Len() > 0 {
+			for _, bid := range book.Bids.Orders() {
+				fmt.Printf("    Price: %s, Quantity: %s, User: %s\n", bid.Price, bid.Quantity, bid.UserID)
+			}
+		} else {
+			fmt.Println("    No bids")
 		}
-	} else {
-		fmt.Println("    No asks")
-	}
 
-	fmt.Printf("\nTeam %s:\n", opposingTeamID)
-	fmt.Println("  Bids (Buy Orders):")
-	if opposingBook.Bids.Len() > 0 {
-		for _, bid := range opposingBook.Bids.Items() {
-			fmt.Printf("    Price: %.2f, Quantity: %.2f, User: %s\n", bid.Price, bid.Quantity, bid.UserID)
+		fmt.Println("  Asks (Lays):")
+		if book.Asks.Len() > 0 {
+			for _, ask := range book.Asks.Orders() {
+				fmt.Printf("    Price: %s, Quantity: %s, User: %s\n", ask.Price, ask.Quantity, ask.UserID)
+			}
+		} else {
+			fmt.Println("    No asks")
 		}
-	} else {
-		fmt.Println("    No bids")
 	}
-	
-	fmt.Println("  Asks (Sell Orders):")
-	if opposingBook.Asks.Len() > 0 {
-		for _, ask := range opposingBook.Asks.Items() {
-			fmt.Printf("    Price: %.2f, Quantity: %.2f, User: %s\n", ask.Price, ask.Quantity, ask.UserID)
-		}
-	} else {
-		fmt.Println("    No asks")
-	}
-	
+
 	fmt.Println("=====================================")
-}
\ No newline at end of file
+}
@@ -0,0 +1,441 @@
+package orderbook
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// openTestMarket registers and opens a market named after the test, and
+// funds each user. Match and user IDs are prefixed with the test name, as
+// the engine's state is shared by every test in the package.
+func openTestMarket(t *testing.T, runners []string, users ...string) (string, func(string) string) {
+	t.Helper()
+	prefix := strings.NewReplacer("/", "-", " ", "-").Replace(t.Name())
+	matchID := prefix + "-match"
+	if err := RegisterMarket(matchID, runners, now().Add(time.Hour), MarketOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := TransitionMarket(matchID, MarketOpen, ""); err != nil {
+		t.Fatal(err)
+	}
+	user := func(name string) string { return prefix + "-" + name }
+	for _, name := range users {
+		if _, err := Deposit(user(name), 10_000_00); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return matchID, user
+}
+
+// place submits an order and fails the test if the engine returns an error.
+func place(t *testing.T, order Order) ExecutionReport {
+	t.Helper()
+	report, err := PlaceOrder(order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+// resting returns the unfilled quantity of a resting order, or 0 if it has
+// left the book.
+func resting(t *testing.T, matchID, orderID string) Money {
+	t.Helper()
+	m, err := lookupMarket(matchID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	qty, _ := execute(m, func() (Money, error) {
+		if _, order := m.findOrder(orderID); order != nil {
+			return order.Quantity, nil
+		}
+		return 0, nil
+	})
+	return qty
+}
+
+// matchTrades returns every trade in a match.
+func matchTrades(t *testing.T, matchID string) []Trade {
+	t.Helper()
+	m, err := lookupMarket(matchID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trades, _ := execute(m, func() ([]Trade, error) {
+		return slices.Clone(m.trades), nil
+	})
+	return trades
+}
+
+// balanceOf returns an account's ledger balance.
+func balanceOf(account string) Money {
+	ledger.Lock()
+	defer ledger.Unlock()
+	return ledger.balances[account]
+}
+
+// checkLedgerBalances checks that every journal entry balances and that
+// the accounts add up to zero between them.
+func checkLedgerBalances(t *testing.T) {
+	t.Helper()
+	ledger.Lock()
+	defer ledger.Unlock()
+	for _, entry := range ledger.entries {
+		if !entry.balanced() {
+			t.Fatalf("entry %d (%s) does not balance", entry.Seq, entry.Kind)
+		}
+	}
+	var total Money
+	for _, balance := range ledger.balances {
+		total += balance
+	}
+	if total != 0 {
+		t.Fatalf("accounts sum to %s", total)
+	}
+}
+
+func TestRegisterMarket(t *testing.T) {
+	matchID := t.Name() + "-match"
+	opts := MarketOptions{SelfTradePolicy: SelfTradeCancelBoth, SurplusPolicy: SurplusToMaker, MaxExposure: 50_00}
+	if err := RegisterMarket(matchID, []string{"a", "b"}, time.Now(), opts); err != nil {
+		t.Fatal(err)
+	}
+	again := MarketOptions{SelfTradePolicy: SelfTradeDecrementBoth}
+	if err := RegisterMarket(matchID, []string{"a", "b", "c"}, time.Now(), again); !errors.Is(err, ErrMatchExists) {
+		t.Fatalf("registering twice: %v", err)
+	}
+
+	// The second registration leaves the first untouched.
+	m, err := lookupMarket(matchID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := execute(m, func() (MarketOptions, error) {
+		if len(m.runners) != 2 {
+			t.Errorf("runners %v", m.runners)
+		}
+		return MarketOptions{m.selfTradePolicy, m.surplusPolicy, m.maxExposure}, nil
+	})
+	if got != opts {
+		t.Errorf("options %+v, want %+v", got, opts)
+	}
+
+	for _, bad := range []MarketOptions{{SelfTradePolicy: "NONE"}, {SurplusPolicy: "NONE"}, {MaxExposure: -1}} {
+		if err := RegisterMarket(t.Name()+"-bad", []string{"a", "b"}, time.Now(), bad); err == nil {
+			t.Errorf("registered with %+v", bad)
+		}
+	}
+	if _, err := lookupMarket(t.Name() + "-bad"); !errors.Is(err, ErrMatchNotFound) {
+		t.Errorf("market with invalid options was registered: %v", err)
+	}
+}
+
+// TestTradeLedger trades a back against a lay and checks that the stake
+// and liability moved from reserved funds into escrow, and that the ledger
+// still sums to zero.
+func TestTradeLedger(t *testing.T) {
+	matchID, user := openTestMarket(t, []string{"a", "b"}, "backer", "layer")
+
+	place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("layer"), Side: "ask", Price: 3_00, Quantity: 10_00})
+	report := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("backer"), Side: "bid", Price: 3_00, Quantity: 4_00})
+	if report.Status != StatusFilled {
+		t.Fatalf("back: %+v", report)
+	}
+
+	if got, want := balanceOf(escrowAccount(matchID)), Money(4_00+8_00); got != want {
+		t.Errorf("escrow holds %s, want %s", got, want)
+	}
+	// The layer still has 6.00 resting at 3.00, a liability of 12.00.
+	if got, want := balanceOf(reservedAccount(user("layer"))), Money(12_00); got != want {
+		t.Errorf("layer has %s reserved, want %s", got, want)
+	}
+	if got := balanceOf(reservedAccount(user("backer"))); got != 0 {
+		t.Errorf("backer has %s reserved after filling", got)
+	}
+	if got, want := balanceOf(availableAccount(user("backer"))), Money(10_000_00-4_00); got != want {
+		t.Errorf("backer has %s available, want %s", got, want)
+	}
+	checkLedgerBalances(t)
+}
+
+func TestOrderIDsAreNeverReused(t *testing.T) {
+	matchID, user := openTestMarket(t, []string{"a", "b"}, "backer", "layer")
+
+	lay := place(t, Order{ID: "lay-1", MatchID: matchID, TeamID: "a", UserID: user("layer"), Side: "ask", Price: 250, Quantity: 10_00})
+	if lay.Status != StatusNew {
+		t.Fatalf("lay: %+v", lay)
+	}
+	back := place(t, Order{ID: "back-1", MatchID: matchID, TeamID: "a", UserID: user("backer"), Side: "bid", Price: 250, Quantity: 10_00})
+	if back.Status != StatusFilled {
+		t.Fatalf("back: %+v", back)
+	}
+
+	// Both orders have left the book; neither ID may be placed again.
+	for _, id := range []string{"lay-1", "back-1"} {
+		report := place(t, Order{ID: id, MatchID: matchID, TeamID: "b", UserID: user("layer"), Side: "ask", Price: 250, Quantity: 1_00})
+		if report.RejectCode != RejectDuplicateOrderID {
+			t.Errorf("reusing %s: %+v", id, report)
+		}
+	}
+
+	// Numeric IDs belong to the engine.
+	report := place(t, Order{ID: "42", MatchID: matchID, TeamID: "b", UserID: user("layer"), Side: "ask", Price: 250, Quantity: 1_00})
+	if report.RejectCode != RejectReservedOrderID {
+		t.Errorf("numeric id: %+v", report)
+	}
+}
+
+// TestSamePriceFillsInAcceptanceOrder checks that orders resting at one
+// price are filled in the order the engine accepted them.
+func TestSamePriceFillsInAcceptanceOrder(t *testing.T) {
+	matchID, user := openTestMarket(t, []string{"a", "b"}, "first", "second", "third", "backer")
+
+	first := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("first"), Side: "ask", Price: 3_00, Quantity: 5_00})
+	second := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("second"), Side: "ask", Price: 3_00, Quantity: 5_00})
+	third := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("third"), Side: "ask", Price: 3_00, Quantity: 5_00})
+
+	report := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("backer"), Side: "bid", Price: 3_00, Quantity: 7_00})
+	if len(report.Fills) != 2 || report.Fills[0].CounterpartyOrderID != first.OrderID || report.Fills[1].CounterpartyOrderID != second.OrderID {
+		t.Fatalf("fills %+v, want %s then %s", report.Fills, first.OrderID, second.OrderID)
+	}
+	for _, want := range []struct {
+		id  string
+		qty Money
+	}{{first.OrderID, 0}, {second.OrderID, 3_00}, {third.OrderID, 5_00}} {
+		if got := resting(t, matchID, want.id); got != want.qty {
+			t.Errorf("order %s rests %s, want %s", want.id, got, want.qty)
+		}
+	}
+}
+
+// TestBetterPriceFillsFirst checks that a later order at a better price
+// is filled ahead of an earlier one.
+func TestBetterPriceFillsFirst(t *testing.T) {
+	matchID, user := openTestMarket(t, []string{"a", "b"}, "early", "better", "backer")
+
+	early := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("early"), Side: "ask", Price: 3_00, Quantity: 5_00})
+	better := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("better"), Side: "ask", Price: 3_50, Quantity: 5_00})
+
+	report := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("backer"), Side: "bid", Price: 3_00, Quantity: 5_00})
+	if len(report.Fills) != 1 || report.Fills[0].CounterpartyOrderID != better.OrderID || report.Fills[0].Price != 3_50 {
+		t.Fatalf("fills %+v, want all of %s at 3.50", report.Fills, better.OrderID)
+	}
+	if got := resting(t, matchID, early.OrderID); got != 5_00 {
+		t.Errorf("earlier order rests %s, want 5.00", got)
+	}
+}
+
+// TestAmendReduceKeepsPriority checks that reducing an order in place
+// keeps it ahead of a later order at the same price.
+func TestAmendReduceKeepsPriority(t *testing.T) {
+	matchID, user := openTestMarket(t, []string{"a", "b"}, "first", "second", "backer")
+
+	first := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("first"), Side: "ask", Price: 3_00, Quantity: 10_00})
+	place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("second"), Side: "ask", Price: 3_00, Quantity: 10_00})
+	amended, err := AmendOrder(matchID, first.OrderID, user("first"), 3_00, 4_00)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if amended.Quantity != 4_00 {
+		t.Fatalf("amended %+v", amended)
+	}
+	// The layer's liability is now only on the 4.00 left.
+	if got, want := balanceOf(reservedAccount(user("first"))), Money(8_00); got != want {
+		t.Errorf("first layer has %s reserved, want %s", got, want)
+	}
+
+	report := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("backer"), Side: "bid", Price: 3_00, Quantity: 4_00})
+	if len(report.Fills) != 1 || report.Fills[0].CounterpartyOrderID != first.OrderID {
+		t.Fatalf("fills %+v, want the reduced order first", report.Fills)
+	}
+}
+
+// TestAmendIncreaseLosesPriority checks that raising an order's size
+// sends it behind later orders at its price, and that a new price that
+// crosses the book trades at once.
+func TestAmendIncreaseLosesPriority(t *testing.T) {
+	matchID, user := openTestMarket(t, []string{"a", "b"}, "first", "second", "backer")
+
+	first := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("first"), Side: "ask", Price: 3_00, Quantity: 5_00})
+	second := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("second"), Side: "ask", Price: 3_00, Quantity: 5_00})
+	if _, err := AmendOrder(matchID, first.OrderID, user("first"), 3_00, 6_00); err != nil {
+		t.Fatal(err)
+	}
+	report := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("backer"), Side: "bid", Price: 3_00, Quantity: 5_00})
+	if len(report.Fills) != 1 || report.Fills[0].CounterpartyOrderID != second.OrderID {
+		t.Fatalf("fills %+v, want the untouched order first", report.Fills)
+	}
+
+	// A back resting above the lay's price; moving the lay up to it trades.
+	back := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("backer"), Side: "bid", Price: 3_50, Quantity: 2_00})
+	if back.Status != StatusNew {
+		t.Fatalf("back: %+v", back)
+	}
+	amended, err := AmendOrder(matchID, first.OrderID, user("first"), 3_50, 6_00)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if amended.Quantity != 4_00 {
+		t.Errorf("amended order rests %s, want 4.00", amended.Quantity)
+	}
+	if got := resting(t, matchID, back.OrderID); got != 0 {
+		t.Errorf("back still rests %s", got)
+	}
+}
+
+// TestAmendExpiredOrder checks that a GTD order past its expiry, but not
+// yet swept, is expired rather than amended back onto the book.
+func TestAmendExpiredOrder(t *testing.T) {
+	advance := isolateEngine(t)
+	matchID, user := openTestMarket(t, []string{"a", "b"}, "layer")
+
+	gtd := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("layer"), Side: "ask", Price: 3_00, Quantity: 5_00,
+		TimeInForce: TimeInForceGTD, ExpiresAt: now().Add(time.Minute)})
+	advance(time.Minute)
+	if _, err := AmendOrder(matchID, gtd.OrderID, user("layer"), 3_00, 4_00); !errors.Is(err, ErrOrderExpired) {
+		t.Fatalf("amending an expired order: %v", err)
+	}
+	if got := resting(t, matchID, gtd.OrderID); got != 0 {
+		t.Errorf("expired order still rests %s", got)
+	}
+	if got := balanceOf(reservedAccount(user("layer"))); got != 0 {
+		t.Errorf("layer has %s still reserved", got)
+	}
+}
+
+// TestIOCCancelsRemainder checks that an IOC order fills what it can and
+// leaves nothing in the book or reserved.
+func TestIOCCancelsRemainder(t *testing.T) {
+	matchID, user := openTestMarket(t, []string{"a", "b"}, "layer", "backer")
+
+	place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("layer"), Side: "ask", Price: 3_00, Quantity: 5_00})
+	report := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("backer"), Side: "bid", Price: 3_00, Quantity: 8_00, TimeInForce: TimeInForceIOC})
+	if report.Status != StatusPartiallyFilled || report.FilledQuantity() != 5_00 || report.Resting != 0 {
+		t.Fatalf("IOC: %+v", report)
+	}
+	if got := resting(t, matchID, report.OrderID); got != 0 {
+		t.Errorf("IOC remainder rests %s", got)
+	}
+	if got := balanceOf(reservedAccount(user("backer"))); got != 0 {
+		t.Errorf("backer has %s reserved after IOC", got)
+	}
+}
+
+// TestFOKFillsCompletelyOrNotAtAll checks that an FOK order that cannot
+// fill in full trades nothing, and one that can fills in full, through
+// cross liquidity as well as its own book.
+func TestFOKFillsCompletelyOrNotAtAll(t *testing.T) {
+	matchID, user := openTestMarket(t, []string{"a", "b", "c"}, "layer", "backer", "maker")
+
+	lay := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("layer"), Side: "ask", Price: 3_00, Quantity: 5_00})
+	killed := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("backer"), Side: "bid", Price: 3_00, Quantity: 8_00, TimeInForce: TimeInForceFOK})
+	if killed.Status != StatusCancelled || len(killed.Fills) != 0 {
+		t.Fatalf("FOK beyond the book: %+v", killed)
+	}
+	if got := resting(t, matchID, lay.OrderID); got != 5_00 {
+		t.Errorf("killed FOK touched the book: lay rests %s", got)
+	}
+	if got := balanceOf(reservedAccount(user("backer"))); got != 0 {
+		t.Errorf("backer has %s reserved after a killed FOK", got)
+	}
+	filled := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("backer"), Side: "bid", Price: 3_00, Quantity: 5_00, TimeInForce: TimeInForceFOK})
+	if filled.Status != StatusFilled {
+		t.Fatalf("FOK within the book: %+v", filled)
+	}
+
+	// Lays of b and c only fill a lay of a by crossing.
+	for _, runner := range []string{"b", "c"} {
+		place(t, Order{MatchID: matchID, TeamID: runner, UserID: user("maker"), Side: "ask", Price: 3_00, Quantity: 10_00})
+	}
+	killed = place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("layer"), Side: "ask", Price: 3_00, Quantity: 1000_00, TimeInForce: TimeInForceFOK})
+	if killed.Status != StatusCancelled || len(killed.Fills) != 0 {
+		t.Fatalf("FOK beyond the cross: %+v", killed)
+	}
+	crossed := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("layer"), Side: "ask", Price: 3_00, Quantity: 5_00, TimeInForce: TimeInForceFOK})
+	if crossed.Status != StatusFilled || crossed.Fills[0].MatchType != MatchCrossTeam {
+		t.Fatalf("FOK through the cross: %+v", crossed)
+	}
+	checkLedgerBalances(t)
+}
+
+// TestGTDExpiry checks that GTD orders are expired by the sweeper once
+// their expiry passes, and by default at the match start, where an
+// incoming order will not trade with them either.
+func TestGTDExpiry(t *testing.T) {
+	advance := isolateEngine(t)
+	matchID, user := openTestMarket(t, []string{"a", "b"}, "layer", "backer")
+
+	swept := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("layer"), Side: "ask", Price: 3_00, Quantity: 5_00,
+		TimeInForce: TimeInForceGTD, ExpiresAt: now().Add(10 * time.Minute)})
+	atStart := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("layer"), Side: "ask", Price: 3_00, Quantity: 5_00,
+		TimeInForce: TimeInForceGTD})
+
+	advance(5 * time.Minute)
+	if expired := ExpireOrders(now()); len(expired) != 0 {
+		t.Fatalf("expired early: %+v", expired)
+	}
+	advance(5 * time.Minute)
+	if expired := ExpireOrders(now()); len(expired) != 1 || expired[0].ID != swept.OrderID {
+		t.Fatalf("expired %+v, want %s", expired, swept.OrderID)
+	}
+	if got := resting(t, matchID, atStart.OrderID); got != 5_00 {
+		t.Fatalf("order expiring at the start rests %s", got)
+	}
+
+	// The match starts; the order is gone before any sweep.
+	advance(time.Hour)
+	report := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("backer"), Side: "bid", Price: 3_00, Quantity: 5_00, TimeInForce: TimeInForceIOC})
+	if len(report.Fills) != 0 {
+		t.Fatalf("traded with an expired order: %+v", report)
+	}
+	if got := resting(t, matchID, atStart.OrderID); got != 0 {
+		t.Errorf("order still rests %s after the match start", got)
+	}
+	if got := balanceOf(reservedAccount(user("layer"))); got != 0 {
+		t.Errorf("layer has %s reserved after both orders expired", got)
+	}
+}
+
+// TestSameTeamBackLayDirection pins the meaning of side and price on one
+// runner: backs rank lowest odds first, lays highest first, and a back
+// and a lay trade only when the lay's odds are at least the back's.
+func TestSameTeamBackLayDirection(t *testing.T) {
+	matchID, user := openTestMarket(t, []string{"a", "b"}, "backer", "layer")
+
+	for _, price := range []Odds{2_50, 2_00, 2_20} {
+		place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("layer"), Side: "ask", Price: price, Quantity: 1_00})
+	}
+	// A back at 3.00 wants longer odds than any lay gives.
+	back := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("backer"), Side: "bid", Price: 3_00, Quantity: 1_00})
+	if len(back.Fills) != 0 {
+		t.Fatalf("back above every lay traded: %+v", back)
+	}
+	for _, price := range []Odds{3_50, 3_20} {
+		place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("backer"), Side: "bid", Price: price, Quantity: 1_00})
+	}
+
+	m, err := lookupMarket(matchID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ranks, _ := execute(m, func() ([2][]Odds, error) {
+		book := m.books["a"]
+		return [2][]Odds{makerOdds(book.Bids.Orders()), makerOdds(book.Asks.Orders())}, nil
+	})
+	if want := []Odds{3_00, 3_20, 3_50}; !slices.Equal(ranks[0], want) {
+		t.Errorf("backs rank %v, want %v", ranks[0], want)
+	}
+	if want := []Odds{2_50, 2_20, 2_00}; !slices.Equal(ranks[1], want) {
+		t.Errorf("lays rank %v, want %v", ranks[1], want)
+	}
+
+	// A lay at 3.20 takes the backs at 3.00 and 3.20, lowest first, at
+	// their prices, and leaves the back at 3.50.
+	lay := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("layer"), Side: "ask", Price: 3_20, Quantity: 3_00})
+	if len(lay.Fills) != 2 || lay.Fills[0].Price != 3_00 || lay.Fills[1].Price != 3_20 || lay.Resting != 1_00 {
+		t.Fatalf("lay at 3.20: %+v", lay)
+	}
+}
@@ -0,0 +1,99 @@
+package orderbook
+
+import "errors"
+
+var (
+	// ErrMatchNotFound is returned for a match that was never registered.
+	ErrMatchNotFound = errors.New("match not found")
+	// ErrMatchExists is returned when a match is registered twice.
+	ErrMatchExists = errors.New("match already registered")
+	// ErrMarketBusy is returned when a market's command queue is full.
+	ErrMarketBusy = errors.New("market is busy, try again")
+	// ErrOrderNotFound is returned when no resting order has the given ID.
+	ErrOrderNotFound = errors.New("order not found")
+	// ErrNotOrderOwner is returned when a user acts on someone else's order.
+	ErrNotOrderOwner = errors.New("order belongs to another user")
+	// ErrOrderExpired is returned when a GTD order is amended after its
+	// expiry; the order is expired instead.
+	ErrOrderExpired = errors.New("order has expired")
+	// ErrInvalidAmendment is returned when an amendment has a non-positive
+	// price or quantity.
+	ErrInvalidAmendment = errors.New("amended price and quantity must be positive")
+	// ErrInvalidSelfTradePolicy is returned for an unknown self-trade
+	// prevention policy.
+	ErrInvalidSelfTradePolicy = errors.New("unknown self-trade prevention policy")
+	// ErrInvalidSurplusPolicy is returned for an unknown cross-match surplus
+	// policy.
+	ErrInvalidSurplusPolicy = errors.New("unknown surplus policy")
+	// ErrInvalidRunners is returned when a market is registered with fewer
+	// than two runners, or with blank or repeated ones.
+	ErrInvalidRunners = errors.New("invalid runners")
+	// ErrInvalidMarketState is returned for an unknown market state.
+	ErrInvalidMarketState = errors.New("unknown market state")
+	// ErrInvalidTransition is returned when a market cannot move from its
+	// current state to the requested one.
+	ErrInvalidTransition = errors.New("invalid market state transition")
+	// ErrMarketNotOpen is returned when orders are amended in a market that
+	// is not trading.
+	ErrMarketNotOpen = errors.New("market is not open for trading")
+	// ErrSettlementRequired is returned when a market is moved to SETTLED
+	// directly instead of through SettleMatch.
+	ErrSettlementRequired = errors.New("markets are settled through SettleMatch")
+	// ErrAlreadySettled is returned when a match is settled again with a
+	// different winner. ResettleMatch corrects a declared result.
+	ErrAlreadySettled = errors.New("match already settled")
+	// ErrUnknownRunner is returned when a result names a runner that is not
+	// in the match.
+	ErrUnknownRunner = errors.New("runner is not in this match")
+	// ErrNotSettled is returned when a match that has not been settled is
+	// resettled.
+	ErrNotSettled = errors.New("match is not settled")
+	// ErrMarketVoided is returned when a voided match is settled.
+	ErrMarketVoided = errors.New("match is voided")
+	// ErrMissingUser is returned when a wallet operation names no user.
+	ErrMissingUser = errors.New("user_id is required")
+	// ErrInvalidAmount is returned for a deposit or withdrawal that is not
+	// positive.
+	ErrInvalidAmount = errors.New("amount must be positive")
+	// ErrInsufficientFunds is returned when a user's available balance does
+	// not cover a withdrawal or an order's reservation.
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrUnbalancedEntry is returned when a journal entry's debits and
+	// credits differ. Nothing in the batch is posted.
+	ErrUnbalancedEntry = errors.New("unbalanced journal entry")
+	// ErrExposureLimit is returned when an order would take a user past the
+	// market's maximum exposure.
+	ErrExposureLimit = errors.New("exposure limit exceeded")
+	// ErrShortReservation is returned when an order would fill for more
+	// than is reserved for it. The fill is rejected.
+	ErrShortReservation = errors.New("reserved funds do not cover the fill")
+	// ErrNothingToCashOut is returned when a user's position already pays
+	// the same whoever wins.
+	ErrNothingToCashOut = errors.New("position is already level")
+	// ErrNoLiquidity is returned when no book has a price to cash out at.
+	ErrNoLiquidity = errors.New("no prices to cash out against")
+	// ErrOrderRejected is returned when an order placed on a user's behalf,
+	// such as a cash-out, is rejected for a reason with no error of its own.
+	ErrOrderRejected = errors.New("order rejected")
+	// ErrInvalidTolerance is returned for a negative cash-out price
+	// tolerance.
+	ErrInvalidTolerance = errors.New("tolerance must not be negative")
+	// ErrSnapshotVersion is returned when restoring a snapshot written in
+	// a format this engine does not read.
+	ErrSnapshotVersion = errors.New("unsupported snapshot version")
+	// ErrEngineNotEmpty is returned when restoring a snapshot into an
+	// engine that already has markets or ledger entries.
+	ErrEngineNotEmpty = errors.New("engine already has state")
+	// ErrCorruptCommandLog is returned when a command log record cannot be
+	// read or is out of sequence.
+	ErrCorruptCommandLog = errors.New("corrupt command log")
+	// ErrReplayDiverged is returned when a logged command is not accepted
+	// on replay, so the engine no longer follows the original run.
+	ErrReplayDiverged = errors.New("replay diverged from command log")
+	// ErrEngineHalted is returned for every command once the command log
+	// could not be written or synced, or a trade could not be logged.
+	ErrEngineHalted = errors.New("engine halted")
+	// ErrTradeOutOfSequence is returned when a trade does not follow the
+	// last one logged for its match.
+	ErrTradeOutOfSequence = errors.New("trade out of sequence")
+)
@@ -0,0 +1,182 @@
+package orderbook
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Prices and stakes are fixed-point integers with two decimal places, so
+// fills and balances add up exactly.
+//
+// Rounding rules:
+//   - Parsing never rounds: inputs with more than two decimal places, or
+//     outside the type's range, are rejected. Odds are never negative;
+//     Money may be, down to -MaxMoney.
+//   - Addition, subtraction and comparison are exact.
+//   - Multiplication and division round to the nearest hundredth, ties to
+//     even (banker's rounding).
+//   - Matching sizes stakes with the directed variants (Down and Up), so
+//     rounding never gives a trader worse odds than they asked for.
+
+const fixedScale = 100
+
+// Odds is a decimal price in hundredths, e.g. 1.85 is Odds(185).
+type Odds int64
+
+// Money is an amount in paise (hundredths of a rupee).
+type Money int64
+
+const (
+	MaxOdds  Odds  = 1000_00            // highest representable odds
+	MaxMoney Money = 100_000_000_000_00 // ₹100 billion
+)
+
+var (
+	// ErrInvalidDecimal is returned for input that is not a plain decimal number.
+	ErrInvalidDecimal = errors.New("invalid decimal")
+	// ErrTooPrecise is returned for input with more than two decimal places.
+	ErrTooPrecise = errors.New("more than two decimal places")
+	// ErrOutOfRange is returned for input beyond MaxOdds or MaxMoney.
+	ErrOutOfRange = errors.New("value out of range")
+)
+
+// ParseOdds parses a decimal string such as "1.85".
+func ParseOdds(s string) (Odds, error) {
+	v, err := parseFixed(s, 0, int64(MaxOdds))
+	return Odds(v), err
+}
+
+// ParseMoney parses a decimal string such as "250.50".
+func ParseMoney(s string) (Money, error) {
+	v, err := parseFixed(s, -int64(MaxMoney), int64(MaxMoney))
+	return Money(v), err
+}
+
+func (o Odds) String() string  { return formatFixed(int64(o)) }
+func (m Money) String() string { return formatFixed(int64(m)) }
+
+// MulOdds returns the stake multiplied by the odds.
+func (m Money) MulOdds(o Odds) Money {
+	return Money(divRound(int64(m)*int64(o), fixedScale))
+}
+
+// MulOddsDown is MulOdds rounded down, for non-negative amounts.
+func (m Money) MulOddsDown(o Odds) Money {
+	return Money(int64(m) * int64(o) / fixedScale)
+}
+
+// DivOddsDown returns the amount divided by the odds, rounded down.
+func (m Money) DivOddsDown(o Odds) Money {
+	return Money(int64(m) * fixedScale / int64(o))
+}
+
+// DivOddsUp returns the amount divided by the odds, rounded up.
+func (m Money) DivOddsUp(o Odds) Money {
+	return Money((int64(m)*fixedScale + int64(o) - 1) / int64(o))
+}
+
+// Liability is what laying a backer's stake of m at odds o risks:
+// m·(o-1), rounded down so the layer never risks more than the odds allow.
+// The backer's winnings are the same amount.
+func (m Money) Liability(o Odds) Money {
+	return Money(int64(m) * int64(o-fixedScale) / fixedScale)
+}
+
+func (o Odds) MarshalJSON() ([]byte, error)  { return []byte(o.String()), nil }
+func (m Money) MarshalJSON() ([]byte, error) { return []byte(m.String()), nil }
+
+func (o *Odds) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalFixed(data, 0, int64(MaxOdds))
+	*o = Odds(v)
+	return err
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalFixed(data, -int64(MaxMoney), int64(MaxMoney))
+	*m = Money(v)
+	return err
+}
+
+// unmarshalFixed accepts a JSON number or a JSON string holding a number.
+func unmarshalFixed(data []byte, min, max int64) (int64, error) {
+	s := string(data)
+	if s == "null" {
+		return 0, nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	return parseFixed(s, min, max)
+}
+
+// parseFixed parses s as a value in hundredths between min and max, where
+// min is zero or -max.
+func parseFixed(s string, min, max int64) (int64, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" || !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > 2 {
+		return 0, fmt.Errorf("%w: %q", ErrTooPrecise, s)
+	}
+	frac += strings.Repeat("0", 2-len(frac))
+
+	w, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || w > max/fixedScale {
+		return 0, fmt.Errorf("%w: %q", ErrOutOfRange, s)
+	}
+	f, _ := strconv.ParseInt(frac, 10, 64)
+	v := w*fixedScale + f
+	if v > max {
+		return 0, fmt.Errorf("%w: %q", ErrOutOfRange, s)
+	}
+	if negative {
+		v = -v
+	}
+	if v < min {
+		return 0, fmt.Errorf("%w: %q", ErrOutOfRange, "-"+s)
+	}
+	return v, nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func formatFixed(v int64) string {
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/fixedScale, v%fixedScale)
+}
+
+// divRound divides n by a positive d, rounding to nearest with ties to even.
+func divRound(n, d int64) int64 {
+	q, r := n/d, n%d
+	if r < 0 {
+		r = -r
+	}
+	switch {
+	case 2*r > d, 2*r == d && q%2 != 0:
+		if n < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return q
+}
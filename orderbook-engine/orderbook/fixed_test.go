@@ -0,0 +1,145 @@
+package orderbook
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseOdds(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Odds
+		err  error
+	}{
+		{"1.85", 185, nil},
+		{"2", 200, nil},
+		{"2.5", 250, nil},
+		{"2.50", 250, nil},
+		{"2.500", 250, nil}, // trailing zeros add no precision
+		{" 3.10 ", 310, nil},
+		{"0", 0, nil},
+		{"1000", MaxOdds, nil},
+		{"1.855", 0, ErrTooPrecise},
+		{"1.001", 0, ErrTooPrecise},
+		{"-1.50", 0, ErrOutOfRange},
+		{"1000.01", 0, ErrOutOfRange},
+		{"99999999999999999999", 0, ErrOutOfRange},
+		{"", 0, ErrInvalidDecimal},
+		{".5", 0, ErrInvalidDecimal},
+		{"+1.5", 0, ErrInvalidDecimal},
+		{"1e2", 0, ErrInvalidDecimal},
+		{"1.5.0", 0, ErrInvalidDecimal},
+		{"--1", 0, ErrInvalidDecimal},
+	}
+	for _, tt := range tests {
+		got, err := ParseOdds(tt.in)
+		if !errors.Is(err, tt.err) || (tt.err == nil && got != tt.want) {
+			t.Errorf("ParseOdds(%q) = %v, %v; want %v, %v", tt.in, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+		err  error
+	}{
+		{"250.50", 25050, nil},
+		{"0.01", 1, nil},
+		{"-12.30", -1230, nil},
+		{"-0", 0, nil},
+		{"100000000000", MaxMoney, nil},
+		{"-100000000000", -MaxMoney, nil},
+		{"0.001", 0, ErrTooPrecise},
+		{"-10.999", 0, ErrTooPrecise},
+		{"100000000000.01", 0, ErrOutOfRange},
+		{"-100000000000.01", 0, ErrOutOfRange},
+		{"9223372036854775807", 0, ErrOutOfRange},
+		{"92233720368547758.07", 0, ErrOutOfRange},
+		{"-", 0, ErrInvalidDecimal},
+		{"12,50", 0, ErrInvalidDecimal},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in)
+		if !errors.Is(err, tt.err) || (tt.err == nil && got != tt.want) {
+			t.Errorf("ParseMoney(%q) = %v, %v; want %v, %v", tt.in, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestUnmarshalFixed(t *testing.T) {
+	var o Odds
+	if err := o.UnmarshalJSON([]byte(`"-2.00"`)); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("negative odds: got %v, want %v", err, ErrOutOfRange)
+	}
+	var m Money
+	if err := m.UnmarshalJSON([]byte(`-2.25`)); err != nil || m != -225 {
+		t.Errorf("negative money: got %v, %v; want -2.25", m, err)
+	}
+}
+
+// The rounding tests use products that land exactly between two hundredths,
+// or just either side, so each direction shows up.
+func TestRounding(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Money
+		want Money
+	}{
+		// MulOdds rounds to nearest, ties to even.
+		{"MulOdds 3.33×1.50 tie up to even", Money(333).MulOdds(150), 500},   // 4.995
+		{"MulOdds 1.03×1.50 tie down to even", Money(103).MulOdds(150), 154}, // 1.545
+		{"MulOdds 1.01×1.01 below half", Money(101).MulOdds(101), 102},       // 1.0201
+		{"MulOdds 1.99×1.99 above half", Money(199).MulOdds(199), 396},       // 3.9601
+		{"MulOdds -3.33×1.50 tie away from odd", Money(-333).MulOdds(150), -500},
+		{"MulOddsDown 3.33×1.50", Money(333).MulOddsDown(150), 499},
+		{"MulOddsDown 1.99×1.99", Money(199).MulOddsDown(199), 396},
+
+		// DivOddsDown and DivOddsUp round towards and away from zero.
+		{"DivOddsDown 1.00/3.00", Money(100).DivOddsDown(300), 33},
+		{"DivOddsUp 1.00/3.00", Money(100).DivOddsUp(300), 34},
+		{"DivOddsDown 2.00/3.00", Money(200).DivOddsDown(300), 66},
+		{"DivOddsUp 2.00/3.00", Money(200).DivOddsUp(300), 67},
+		{"DivOddsDown exact", Money(300).DivOddsDown(150), 200},
+		{"DivOddsUp exact", Money(300).DivOddsUp(150), 200},
+
+		// Liability rounds down, so the layer never risks more than
+		// stake·(odds-1).
+		{"Liability 3.33 at 2.50", Money(333).Liability(250), 499}, // 4.995
+		{"Liability 0.99 at 1.01", Money(99).Liability(101), 0},    // 0.0099
+		{"Liability 1.00 at 1.01", Money(100).Liability(101), 1},
+		{"Liability 10.00 at 3.00", Money(1000).Liability(300), 2000},
+		{"Liability at evens", Money(1234).Liability(200), 1234},
+		{"Liability at 1.00", Money(1234).Liability(100), 0},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+// TestDirectedRoundingBounds checks the directed variants against the exact
+// quotient over a range of inputs: Down never exceeds it, Up never falls
+// short of it, and both are within a hundredth.
+func TestDirectedRoundingBounds(t *testing.T) {
+	for m := Money(1); m <= 500; m += 7 {
+		for o := Odds(101); o <= 1000; o += 13 {
+			down, up := m.DivOddsDown(o), m.DivOddsUp(o)
+			// down·o ≤ m·100 ≤ up·o, with no room for one more hundredth.
+			exact := int64(m) * fixedScale
+			if int64(down)*int64(o) > exact || int64(down+1)*int64(o) <= exact {
+				t.Fatalf("%s.DivOddsDown(%s) = %s", m, o, down)
+			}
+			if int64(up)*int64(o) < exact || int64(up-1)*int64(o) >= exact {
+				t.Fatalf("%s.DivOddsUp(%s) = %s", m, o, up)
+			}
+			liability := m.Liability(o)
+			risk := int64(m) * int64(o-fixedScale)
+			if int64(liability)*fixedScale > risk || int64(liability+1)*fixedScale <= risk {
+				t.Fatalf("%s.Liability(%s) = %s", m, o, liability)
+			}
+		}
+	}
+}
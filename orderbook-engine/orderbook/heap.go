@@ -1,12 +1,15 @@
 package orderbook
 
+// Heap is a binary heap that also tracks the position of every element, so
+// an element can be removed in O(log n) without scanning the slice.
 type Heap[T comparable] struct {
-	data []T
-	less func(a, b T) bool
+	data  []T
+	index map[T]int
+	less  func(a, b T) bool
 }
 
 func New[T comparable](less func(a, b T) bool) *Heap[T] {
-	return &Heap[T]{data: []T{}, less: less}
+	return &Heap[T]{data: []T{}, index: make(map[T]int), less: less}
 }
 
 func (h *Heap[T]) Len() int {
@@ -23,6 +26,7 @@ func (h *Heap[T]) Peek() T {
 
 func (h *Heap[T]) Push(x T) {
 	h.data = append(h.data, x)
+	h.index[x] = len(h.data) - 1
 	h.bubbleUp(len(h.data) - 1)
 }
 
@@ -33,21 +37,17 @@ func (h *Heap[T]) Pop() (T, bool) {
 	}
 	top := h.data[0]
 	last := len(h.data) - 1
-	h.data[0] = h.data[last]
+	h.swap(0, last)
 	h.data = h.data[:last]
+	delete(h.index, top)
 	h.bubbleDown(0)
 	return top, true
 }
 
+// Remove deletes x from the heap using the position index.
 func (h *Heap[T]) Remove(x T) (T, bool) {
-	index := -1
-	for i, val := range h.data {
-		if val == x {
-			index = i
-			break
-		}
-	}
-	if index == -1 {
+	index, ok := h.index[x]
+	if !ok {
 		var zero T
 		return zero, false
 	}
@@ -55,6 +55,7 @@ func (h *Heap[T]) Remove(x T) (T, bool) {
 	last := len(h.data) - 1
 	h.swap(index, last)
 	h.data = h.data[:last]
+	delete(h.index, removed)
 	if index < len(h.data) {
 		h.bubbleUp(index)
 		h.bubbleDown(index)
@@ -81,7 +82,7 @@ func (h *Heap[T]) bubbleUp(i int) {
 func (h *Heap[T]) bubbleDown(i int) {
 	n := len(h.data)
 	for {
-		left, right := 2*i + 1, 2*i + 2
+		left, right := 2*i+1, 2*i+2
 		smallest := i
 
 		if left < n && h.less(h.data[left], h.data[smallest]) {
@@ -100,4 +101,6 @@ func (h *Heap[T]) bubbleDown(i int) {
 
 func (h *Heap[T]) swap(i, j int) {
 	h.data[i], h.data[j] = h.data[j], h.data[i]
+	h.index[h.data[i]] = i
+	h.index[h.data[j]] = j
 }
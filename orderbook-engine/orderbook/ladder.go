@@ -0,0 +1,217 @@
+package orderbook
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+// LadderBand is a price range with a fixed tick size. From and To are both
+// valid prices; To is the first price of the next band.
+type LadderBand struct {
+	From Odds
+	To   Odds
+	Tick Odds
+}
+
+// Ladder is the set of prices orders may be placed at.
+type Ladder struct {
+	bands []LadderBand
+	// offsets[i] is the tick index of bands[i].From.
+	offsets []int
+}
+
+// DefaultLadder is the Betfair-style ladder from 1.01 to 1000.
+var DefaultLadder = mustLadder([]LadderBand{
+	{From: 1_01, To: 2_00, Tick: 1},
+	{From: 2_00, To: 3_00, Tick: 2},
+	{From: 3_00, To: 4_00, Tick: 5},
+	{From: 4_00, To: 6_00, Tick: 10},
+	{From: 6_00, To: 10_00, Tick: 20},
+	{From: 10_00, To: 20_00, Tick: 50},
+	{From: 20_00, To: 30_00, Tick: 1_00},
+	{From: 30_00, To: 50_00, Tick: 2_00},
+	{From: 50_00, To: 100_00, Tick: 5_00},
+	{From: 100_00, To: 1000_00, Tick: 10_00},
+})
+
+// ErrOffLadder is returned for a price that is not a tick on the ladder.
+var ErrOffLadder = errors.New("price is not on the odds ladder")
+
+// NewLadder builds a ladder from contiguous bands in ascending order.
+func NewLadder(bands []LadderBand) (*Ladder, error) {
+	if len(bands) == 0 {
+		return nil, errors.New("ladder needs at least one band")
+	}
+	l := &Ladder{bands: bands, offsets: make([]int, len(bands))}
+	offset := 0
+	for i, b := range bands {
+		if b.Tick <= 0 || b.To <= b.From || (b.To-b.From)%b.Tick != 0 {
+			return nil, fmt.Errorf("band %s-%s: tick %s does not divide the range", b.From, b.To, b.Tick)
+		}
+		if i > 0 && bands[i-1].To != b.From {
+			return nil, fmt.Errorf("band %s-%s does not start where the previous band ends", b.From, b.To)
+		}
+		l.offsets[i] = offset
+		offset += int((b.To - b.From) / b.Tick)
+	}
+	return l, nil
+}
+
+func mustLadder(bands []LadderBand) *Ladder {
+	l, err := NewLadder(bands)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+// Min returns the lowest price on the ladder.
+func (l *Ladder) Min() Odds { return l.bands[0].From }
+
+// Max returns the highest price on the ladder.
+func (l *Ladder) Max() Odds { return l.bands[len(l.bands)-1].To }
+
+// band returns the index of the band whose range contains price. Band
+// boundaries belong to the higher band, except for the ladder maximum.
+func (l *Ladder) band(price Odds) (int, bool) {
+	if price < l.Min() || price > l.Max() {
+		return 0, false
+	}
+	for i, b := range l.bands {
+		if price < b.To {
+			return i, true
+		}
+	}
+	return len(l.bands) - 1, true
+}
+
+// Valid reports whether price is exactly on a tick.
+func (l *Ladder) Valid(price Odds) bool {
+	i, ok := l.band(price)
+	return ok && (price-l.bands[i].From)%l.bands[i].Tick == 0
+}
+
+// Index returns the position of price on the ladder, counting from 0 at Min.
+func (l *Ladder) Index(price Odds) (int, error) {
+	if !l.Valid(price) {
+		return 0, fmt.Errorf("%w: %s", ErrOffLadder, price)
+	}
+	i, _ := l.band(price)
+	return l.offsets[i] + int((price-l.bands[i].From)/l.bands[i].Tick), nil
+}
+
+// At returns the price at ladder position index.
+func (l *Ladder) At(index int) (Odds, bool) {
+	for i := len(l.bands) - 1; i >= 0; i-- {
+		if index >= l.offsets[i] {
+			price := l.bands[i].From + Odds(index-l.offsets[i])*l.bands[i].Tick
+			return price, index >= 0 && price <= l.Max()
+		}
+	}
+	return 0, false
+}
+
+// Next returns the tick above price.
+func (l *Ladder) Next(price Odds) (Odds, bool) {
+	index, err := l.Index(price)
+	if err != nil {
+		return 0, false
+	}
+	return l.At(index + 1)
+}
+
+// Prev returns the tick below price.
+func (l *Ladder) Prev(price Odds) (Odds, bool) {
+	index, err := l.Index(price)
+	if err != nil {
+		return 0, false
+	}
+	return l.At(index - 1)
+}
+
+// TicksBetween returns the signed number of ticks from a to b.
+func (l *Ladder) TicksBetween(a, b Odds) (int, error) {
+	ia, err := l.Index(a)
+	if err != nil {
+		return 0, err
+	}
+	ib, err := l.Index(b)
+	if err != nil {
+		return 0, err
+	}
+	return ib - ia, nil
+}
+
+// SnapDown returns the highest tick at or below price.
+func (l *Ladder) SnapDown(price Odds) (Odds, bool) {
+	i, ok := l.band(price)
+	if !ok {
+		return 0, false
+	}
+	b := l.bands[i]
+	return b.From + (price-b.From)/b.Tick*b.Tick, true
+}
+
+// SnapUp returns the lowest tick at or above price.
+func (l *Ladder) SnapUp(price Odds) (Odds, bool) {
+	down, ok := l.SnapDown(price)
+	if !ok || down == price {
+		return down, ok
+	}
+	return l.Next(down)
+}
+
+// --- Engine Ladder Settings ---
+
+// LadderPolicy decides what happens to an order priced between ticks.
+type LadderPolicy int
+
+const (
+	LadderReject LadderPolicy = iota // reject off-ladder prices
+	LadderSnap                       // move the price to the nearest less aggressive tick
+)
+
+var (
+	oddsLadder   = DefaultLadder
+	ladderPolicy = LadderReject
+)
+
+// SetOddsLadder replaces the ladder and off-ladder policy used by the engine.
+func SetOddsLadder(l *Ladder, policy LadderPolicy) {
+	mu.Lock()
+	defer mu.Unlock()
+	oddsLadder = l
+	ladderPolicy = policy
+}
+
+// OddsLadder returns the ladder used by the engine.
+func OddsLadder() *Ladder {
+	mu.RLock()
+	defer mu.RUnlock()
+	return oddsLadder
+}
+
+// alignToLadder validates price for an order on side, snapping it to a tick
+// if the policy allows. Snapping never makes an order more aggressive.
+func alignToLadder(price Odds, side string) (Odds, error) {
+	mu.RLock()
+	l, policy := oddsLadder, ladderPolicy
+	mu.RUnlock()
+
+	if l.Valid(price) {
+		return price, nil
+	}
+	if policy == LadderSnap {
+		// Backs get more aggressive as odds fall, lays as they rise.
+		snapped, ok := l.SnapDown(price)
+		if side == "bid" {
+			snapped, ok = l.SnapUp(price)
+		}
+		if ok {
+			log.Printf("Snapped %s price %s to ladder tick %s", side, price, snapped)
+			return snapped, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s", ErrOffLadder, price)
+}
@@ -0,0 +1,145 @@
+package orderbook
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Ledger accounts that belong to no user.
+const (
+	// HouseAccount takes the other side of every user's settlement and
+	// keeps any cross-match surplus.
+	HouseAccount = "house"
+	// FundingAccount is the outside world: deposits come from it and
+	// withdrawals go to it, so its balance is minus all user money.
+	FundingAccount = "funding"
+)
+
+// availableAccount holds the money a user can bet or withdraw.
+func availableAccount(userID string) string { return "user:" + userID + ":available" }
+
+// reservedAccount holds a user's money set aside for resting orders.
+func reservedAccount(userID string) string { return "user:" + userID + ":reserved" }
+
+// escrowAccount holds the risk of every trade in a match until it is
+// settled or voided.
+func escrowAccount(matchID string) string { return "escrow:" + matchID }
+
+// Journal entry kinds.
+const (
+	EntryDeposit    = "deposit"
+	EntryWithdrawal = "withdrawal"
+	EntryReserve    = "reserve"    // held for a resting order
+	EntryRelease    = "release"    // no longer needed by an order
+	EntryFill       = "fill"       // reserved risk into escrow
+	EntrySettlement = "settlement" // escrow paid out to winners and the house
+	EntryRefund     = "refund"     // escrow returned on a void
+	EntryReversal   = "reversal"
+)
+
+// Posting is one side of a journal entry: a credit to an account when the
+// amount is positive, a debit when it is negative.
+type Posting struct {
+	Account string `json:"account"`
+	Amount  Money  `json:"amount"`
+}
+
+// JournalEntry moves money between accounts. Its postings always sum to
+// zero, so debits equal credits. Entries are never changed once posted; a
+// mistake is undone by posting a reversal.
+type JournalEntry struct {
+	Seq       uint64    `json:"seq"`
+	Command   uint64    `json:"command,omitempty"` // Seq of the command that posted it
+	Kind      string    `json:"kind"`
+	MatchID   string    `json:"match_id,omitempty"`
+	Ref       string    `json:"ref,omitempty"`      // trade ID for fills, order ID for reservations
+	Reverses  uint64    `json:"reverses,omitempty"` // Seq of the entry a reversal cancels
+	Postings  []Posting `json:"postings"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// balanced reports whether the entry's debits equal its credits.
+func (e JournalEntry) balanced() bool {
+	var sum Money
+	for _, p := range e.Postings {
+		sum += p.Amount
+	}
+	return sum == 0
+}
+
+// journal is the append-only record of every entry and the balances it
+// implies.
+type journal struct {
+	sync.Mutex
+	entries  []JournalEntry
+	balances map[string]Money // account → sum of its postings
+}
+
+var ledger = &journal{balances: make(map[string]Money)}
+
+// post appends entries to the journal together for the command st: if any
+// is unbalanced none is posted. It returns the entries as posted.
+func post(st stamp, entries ...JournalEntry) ([]JournalEntry, error) {
+	ledger.Lock()
+	defer ledger.Unlock()
+	return ledger.append(st, entries)
+}
+
+// append posts entries; the caller holds the lock.
+func (j *journal) append(st stamp, entries []JournalEntry) ([]JournalEntry, error) {
+	for _, entry := range entries {
+		if !entry.balanced() {
+			return nil, fmt.Errorf("%w: %s entry for %q", ErrUnbalancedEntry, entry.Kind, entry.MatchID)
+		}
+	}
+	posted := make([]JournalEntry, 0, len(entries))
+	for _, entry := range entries {
+		entry.Seq = uint64(len(j.entries)) + 1
+		entry.Command = st.seq
+		entry.Timestamp = st.at
+		j.entries = append(j.entries, entry)
+		for _, p := range entry.Postings {
+			j.balances[p.Account] += p.Amount
+		}
+		posted = append(posted, entry)
+	}
+	return posted, nil
+}
+
+// reversal cancels entry with an equal and opposite one.
+func reversal(entry JournalEntry) JournalEntry {
+	postings := make([]Posting, 0, len(entry.Postings))
+	for _, p := range entry.Postings {
+		postings = append(postings, Posting{Account: p.Account, Amount: -p.Amount})
+	}
+	return JournalEntry{
+		Kind:     EntryReversal,
+		MatchID:  entry.MatchID,
+		Ref:      entry.Ref,
+		Reverses: entry.Seq,
+		Postings: postings,
+	}
+}
+
+// addPosting adds amount to account's posting, skipping zero amounts so
+// entries only list accounts that move.
+func addPosting(postings []Posting, account string, amount Money) []Posting {
+	if amount == 0 {
+		return postings
+	}
+	for i := range postings {
+		if postings[i].Account == account {
+			postings[i].Amount += amount
+			return postings
+		}
+	}
+	return append(postings, Posting{Account: account, Amount: amount})
+}
+
+// HouseBalance returns what the house has made across all settled matches.
+func HouseBalance() Money {
+	ledger.Lock()
+	defer ledger.Unlock()
+	return ledger.balances[HouseAccount]
+}
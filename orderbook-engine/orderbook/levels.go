@@ -0,0 +1,153 @@
+package orderbook
+
+import "container/list"
+
+// maxLevelHeight bounds the skip list; 2^16 price levels is far more than
+// any ladder has ticks.
+const maxLevelHeight = 16
+
+// PriceLevels holds one side of a runner's book as price levels in priority
+// order, each a FIFO queue of the orders resting at that price. Levels sit
+// in a skip list, so the best order is O(1) and adding or removing an order
+// is O(log levels); an index from order to queue element keeps removal from
+// scanning.
+type PriceLevels struct {
+	before  func(a, b Odds) bool // a is the better price for a taker
+	head    priceLevel           // sentinel; head.next[i] is the first level at height i
+	height  int
+	seed    uint64 // drives level heights; fixed so books build the same way every run
+	byPrice map[Odds]*priceLevel
+	byOrder map[*Order]*list.Element
+}
+
+// priceLevel is every order resting at one price, oldest first.
+type priceLevel struct {
+	price  Odds
+	orders list.List // of *Order
+	next   []*priceLevel
+}
+
+func newPriceLevels(before func(a, b Odds) bool) *PriceLevels {
+	return &PriceLevels{
+		before:  before,
+		head:    priceLevel{next: make([]*priceLevel, maxLevelHeight)},
+		height:  1,
+		seed:    0x9e3779b97f4a7c15,
+		byPrice: make(map[Odds]*priceLevel),
+		byOrder: make(map[*Order]*list.Element),
+	}
+}
+
+// Len returns the number of resting orders.
+func (s *PriceLevels) Len() int {
+	return len(s.byOrder)
+}
+
+// Peek returns the order with the best price and earliest arrival, or nil.
+func (s *PriceLevels) Peek() *Order {
+	best := s.head.next[0]
+	if best == nil {
+		return nil
+	}
+	return best.orders.Front().Value.(*Order)
+}
+
+// Push queues order behind every order already resting at its price.
+func (s *PriceLevels) Push(order *Order) {
+	level, ok := s.byPrice[order.Price]
+	if !ok {
+		level = s.insertLevel(order.Price)
+	}
+	s.byOrder[order] = level.orders.PushBack(order)
+}
+
+// Remove takes order out of its queue, dropping the level once it is
+// empty. The order's price must not have changed since it was pushed.
+func (s *PriceLevels) Remove(order *Order) bool {
+	elem, ok := s.byOrder[order]
+	if !ok {
+		return false
+	}
+	level := s.byPrice[order.Price]
+	level.orders.Remove(elem)
+	delete(s.byOrder, order)
+	if level.orders.Len() == 0 {
+		s.removeLevel(level.price)
+	}
+	return true
+}
+
+// Orders returns every resting order in priority order.
+func (s *PriceLevels) Orders() []*Order {
+	orders := make([]*Order, 0, s.Len())
+	s.eachLevel(func(level *priceLevel) bool {
+		for e := level.orders.Front(); e != nil; e = e.Next() {
+			orders = append(orders, e.Value.(*Order))
+		}
+		return true
+	})
+	return orders
+}
+
+// eachLevel calls fn with every level, best first, until fn returns false.
+func (s *PriceLevels) eachLevel(fn func(level *priceLevel) bool) {
+	for level := s.head.next[0]; level != nil; level = level.next[0] {
+		if !fn(level) {
+			return
+		}
+	}
+}
+
+// path returns, for each height, the last level before price.
+func (s *PriceLevels) path(price Odds) [maxLevelHeight]*priceLevel {
+	var update [maxLevelHeight]*priceLevel
+	at := &s.head
+	for i := s.height - 1; i >= 0; i-- {
+		for at.next[i] != nil && s.before(at.next[i].price, price) {
+			at = at.next[i]
+		}
+		update[i] = at
+	}
+	return update
+}
+
+func (s *PriceLevels) insertLevel(price Odds) *priceLevel {
+	update := s.path(price)
+	height := s.randomHeight()
+	for ; s.height < height; s.height++ {
+		update[s.height] = &s.head
+	}
+
+	level := &priceLevel{price: price, next: make([]*priceLevel, height)}
+	for i := 0; i < height; i++ {
+		level.next[i] = update[i].next[i]
+		update[i].next[i] = level
+	}
+	s.byPrice[price] = level
+	return level
+}
+
+func (s *PriceLevels) removeLevel(price Odds) {
+	update := s.path(price)
+	level := s.byPrice[price]
+	for i := range level.next {
+		update[i].next[i] = level.next[i]
+	}
+	for s.height > 1 && s.head.next[s.height-1] == nil {
+		s.height--
+	}
+	delete(s.byPrice, price)
+}
+
+// randomHeight picks a level height with P(h) = 2^-h, from an xorshift
+// generator so the structure does not depend on a global source.
+func (s *PriceLevels) randomHeight() int {
+	s.seed ^= s.seed << 13
+	s.seed ^= s.seed >> 7
+	s.seed ^= s.seed << 17
+	height := 1
+	for bits := s.seed; height < maxLevelHeight && bits&1 == 1; bits >>= 1 {
+		height++
+	}
+	return height
+}
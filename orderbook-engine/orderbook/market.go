@@ -0,0 +1,218 @@
+package orderbook
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// commandQueueSize bounds how many commands may wait on a single market.
+const commandQueueSize = 1024
+
+// market owns the order books of one match. All reads and writes to the
+// books run on the market's goroutine, in the order commands were queued,
+// so books need no locks of their own.
+type market struct {
+	id       string
+	runners  []string // team IDs, plus "draw" or the field where a market has them
+	start    time.Time
+	books    map[string]*OrderBook // teamID → OrderBook
+	commands chan func()
+	state    MarketState
+	cmd      stamp               // the command being applied
+	traded   int                 // trades recorded by that command so far
+	trades   []Trade             // every execution, in order
+	orderIDs map[string]struct{} // every order ID the market has accepted
+
+	positions map[string]*position // userID → position from those trades
+
+	settlement *SettlementReport // set once the match is settled or voided
+	posted     []JournalEntry    // settlement entries not yet reversed
+
+	selfTradePolicy SelfTradePolicy
+	surplusPolicy   SurplusPolicy
+
+	reserved    map[string]Money // orderID → funds held for it
+	exposure    map[string]Money // userID → reserved plus matched risk
+	maxExposure Money            // per user; zero for no limit
+}
+
+// --- Storage for Match Data ---
+
+var (
+	markets = make(map[string]*market) // matchID → market
+	mu      sync.RWMutex
+)
+
+// --- Match Registration ---
+
+// RegisterMatch registers a head-to-head match between two teams.
+func RegisterMatch(matchID, teamA, teamB string, startTime time.Time) {
+	if err := RegisterMarket(matchID, []string{teamA, teamB}, startTime, MarketOptions{}); err != nil {
+		log.Printf("Match %s not registered: %v", matchID, err)
+	}
+}
+
+// MarketOptions are the policies a market is registered with. A zero field
+// takes the default.
+type MarketOptions struct {
+	SelfTradePolicy SelfTradePolicy `json:"self_trade_policy,omitempty"`
+	SurplusPolicy   SurplusPolicy   `json:"surplus_policy,omitempty"`
+	MaxExposure     Money           `json:"max_exposure,omitempty"` // per user; zero for no limit
+}
+
+// validate checks every option that is set.
+func (o MarketOptions) validate() error {
+	if o.SelfTradePolicy != "" && !o.SelfTradePolicy.Valid() {
+		return ErrInvalidSelfTradePolicy
+	}
+	if o.SurplusPolicy != "" && !o.SurplusPolicy.Valid() {
+		return ErrInvalidSurplusPolicy
+	}
+	if o.MaxExposure < 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidAmount, o.MaxExposure)
+	}
+	return nil
+}
+
+// RegisterMarket registers a match with any number of runners, such as
+// home, draw and away, or every entrant in an outright market, together
+// with its policies. A match is registered once; registering it again
+// returns ErrMatchExists and leaves the first registration as it was.
+func RegisterMarket(matchID string, runners []string, startTime time.Time, opts MarketOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	m, err := newMarket(matchID, runners, startTime)
+	if err != nil {
+		return err
+	}
+	if opts.SelfTradePolicy != "" {
+		m.selfTradePolicy = opts.SelfTradePolicy
+	}
+	if opts.SurplusPolicy != "" {
+		m.surplusPolicy = opts.SurplusPolicy
+	}
+	m.maxExposure = opts.MaxExposure
+
+	cmd := Command{Type: CommandRegister, MatchID: matchID, Runners: m.runners, Start: startTime, Options: &opts}
+	_, err = engineCommand(cmd, func(stamp) (struct{}, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if _, exists := markets[matchID]; exists {
+			return struct{}{}, fmt.Errorf("%w: %s", ErrMatchExists, matchID)
+		}
+		markets[matchID] = m
+		go m.run()
+		return struct{}{}, nil
+	})
+	return err
+}
+
+// newMarket builds an empty, pending market. It is not started.
+func newMarket(matchID string, runners []string, startTime time.Time) (*market, error) {
+	books := make(map[string]*OrderBook, len(runners))
+	for _, runner := range runners {
+		if _, dup := books[runner]; dup || runner == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRunners, runner)
+		}
+		books[runner] = newOrderBook()
+	}
+	if len(books) < 2 {
+		return nil, fmt.Errorf("%w: need at least two", ErrInvalidRunners)
+	}
+
+	return &market{
+		id:              matchID,
+		runners:         append([]string(nil), runners...),
+		start:           startTime,
+		books:           books,
+		commands:        make(chan func(), commandQueueSize),
+		state:           MarketPending,
+		orderIDs:        make(map[string]struct{}),
+		positions:       make(map[string]*position),
+		selfTradePolicy: DefaultSelfTradePolicy,
+		surplusPolicy:   DefaultSurplusPolicy,
+		reserved:        make(map[string]Money),
+		exposure:        make(map[string]Money),
+		maxExposure:     DefaultMaxExposure,
+	}, nil
+}
+
+func lookupMarket(matchID string) (*market, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	m, ok := markets[matchID]
+	if !ok {
+		return nil, ErrMatchNotFound
+	}
+	return m, nil
+}
+
+func allMarkets() []*market {
+	mu.RLock()
+	defer mu.RUnlock()
+	list := make([]*market, 0, len(markets))
+	for _, m := range markets {
+		list = append(list, m)
+	}
+	return list
+}
+
+// --- Command Queue ---
+
+func (m *market) run() {
+	for cmd := range m.commands {
+		cmd()
+	}
+}
+
+// submit queues cmd on the market and waits for it to run. It fails fast
+// with ErrMarketBusy when the queue is full instead of blocking the caller.
+func (m *market) submit(cmd func()) error {
+	done := make(chan struct{})
+	select {
+	case m.commands <- func() { defer close(done); cmd() }:
+	default:
+		return ErrMarketBusy
+	}
+	<-done
+	return nil
+}
+
+// execute runs fn on the market goroutine and returns its result. It is for
+// queries; anything that changes the market goes through marketCommand so
+// that it is logged.
+func execute[T any](m *market, fn func() (T, error)) (T, error) {
+	var result T
+	var err error
+	if submitErr := m.submit(func() { result, err = fn() }); submitErr != nil {
+		return result, submitErr
+	}
+	return result, err
+}
+
+// --- Runner Lookup ---
+
+// otherRunners returns every runner in the market except teamID.
+func (m *market) otherRunners(teamID string) []string {
+	others := make([]string, 0, len(m.runners)-1)
+	for _, runner := range m.runners {
+		if runner != teamID {
+			others = append(others, runner)
+		}
+	}
+	return others
+}
+
+// findOrder returns a resting order and the book it rests in.
+func (m *market) findOrder(orderID string) (*OrderBook, *Order) {
+	for _, runner := range m.runners {
+		book := m.books[runner]
+		if order, ok := book.orders[orderID]; ok {
+			return book, order
+		}
+	}
+	return nil, nil
+}
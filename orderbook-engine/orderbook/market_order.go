@@ -0,0 +1,26 @@
+package orderbook
+
+// Order types for Order.Type.
+const (
+	OrderTypeLimit  = "limit"  // trade at Price or better, rest the remainder
+	OrderTypeMarket = "market" // trade at the best available prices, never rest
+)
+
+// acceptsPrice reports whether the order is willing to trade at price. Limit
+// orders are bounded by Price; market orders by WorstPrice, which only
+// backs may leave unset.
+func (o *Order) acceptsPrice(price Odds) bool {
+	limit := o.Price
+	if o.Type == OrderTypeMarket {
+		if o.WorstPrice <= 0 {
+			return true
+		}
+		limit = o.WorstPrice
+	}
+
+	// A backer wants odds at least its limit, a layer at most its limit.
+	if o.Side == "bid" {
+		return price >= limit
+	}
+	return price <= limit
+}
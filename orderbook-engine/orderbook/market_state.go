@@ -0,0 +1,126 @@
+package orderbook
+
+import (
+	"fmt"
+	"log"
+)
+
+// MarketState is where a market is in its lifecycle.
+type MarketState string
+
+const (
+	MarketPending   MarketState = "PENDING"   // registered, not yet trading
+	MarketOpen      MarketState = "OPEN"      // trading before the start
+	MarketSuspended MarketState = "SUSPENDED" // trading halted, resting orders kept
+	MarketInPlay    MarketState = "IN_PLAY"   // trading while the match is on
+	MarketClosed    MarketState = "CLOSED"    // trading over, awaiting a result
+	MarketSettled   MarketState = "SETTLED"   // result declared and paid out
+	MarketVoided    MarketState = "VOIDED"    // called off
+)
+
+// marketTransitions lists the states each state may move to.
+var marketTransitions = map[MarketState][]MarketState{
+	MarketPending:   {MarketOpen, MarketVoided},
+	MarketOpen:      {MarketSuspended, MarketInPlay, MarketClosed, MarketVoided},
+	MarketSuspended: {MarketOpen, MarketInPlay, MarketClosed, MarketVoided},
+	MarketInPlay:    {MarketSuspended, MarketClosed, MarketVoided},
+	MarketClosed:    {MarketSettled, MarketVoided},
+	MarketSettled:   {MarketVoided},
+	MarketVoided:    {},
+}
+
+// Valid reports whether s is one of the known states.
+func (s MarketState) Valid() bool {
+	_, ok := marketTransitions[s]
+	return ok
+}
+
+// canMoveTo reports whether a market in s may move to next.
+func (s MarketState) canMoveTo(next MarketState) bool {
+	for _, allowed := range marketTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsOrders reports whether orders may be placed or amended in s.
+func (s MarketState) acceptsOrders() bool {
+	return s == MarketOpen || s == MarketInPlay
+}
+
+// TransitionMarket moves a match to state and returns the state it left.
+// Closing or voiding a market cancels every resting order, and voiding it
+// refunds every trade as VoidMatch does. Only SettleMatch moves a market to
+// SETTLED.
+func TransitionMarket(matchID string, state MarketState, reason string) (MarketState, error) {
+	if !state.Valid() {
+		return "", fmt.Errorf("%w: %q", ErrInvalidMarketState, state)
+	}
+	if state == MarketSettled {
+		return "", ErrSettlementRequired
+	}
+	m, err := lookupMarket(matchID)
+	if err != nil {
+		return "", err
+	}
+	cmd := Command{Type: CommandTransition, MatchID: m.id, State: state, Reason: reason}
+	return marketCommand(m, cmd, func() (MarketState, error) {
+		if state == MarketVoided {
+			from := m.state
+			_, err := m.void(reason)
+			return from, err
+		}
+		return m.transition(state, reason)
+	})
+}
+
+// GetMarketState returns the current state of a match.
+func GetMarketState(matchID string) (MarketState, error) {
+	m, err := lookupMarket(matchID)
+	if err != nil {
+		return "", err
+	}
+	return execute(m, func() (MarketState, error) {
+		return m.state, nil
+	})
+}
+
+func (m *market) transition(state MarketState, reason string) (MarketState, error) {
+	from := m.state
+	if !from.canMoveTo(state) {
+		return from, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, from, state)
+	}
+	m.state = state
+	log.Printf("Match %s: %s -> %s (%s)", m.id, from, state, reason)
+
+	if state == MarketClosed || state == MarketVoided {
+		m.cancelAllOrders()
+	}
+	PublishMarketEvent(MarketEvent{
+		MatchID:   m.id,
+		From:      from,
+		To:        state,
+		Reason:    reason,
+		Timestamp: m.cmd.at,
+	})
+	return from, nil
+}
+
+// cancelAllOrders removes every resting order in the market.
+func (m *market) cancelAllOrders() {
+	for _, runner := range m.runners {
+		book := m.books[runner]
+		for _, order := range book.Bids.Orders() {
+			book.remove(order)
+			m.release(order)
+			PublishOrderEvent(OrderEventCancelled, *order)
+		}
+		for _, order := range book.Asks.Orders() {
+			book.remove(order)
+			m.release(order)
+			PublishOrderEvent(OrderEventCancelled, *order)
+		}
+	}
+}
@@ -1,11 +1,26 @@
 package orderbook
 
+import "time"
+
+// Order is a bet offered on one runner, priced in decimal odds. A bid backs
+// TeamID and takes any lay at Price or higher odds; an ask lays TeamID and
+// takes any back at Price or lower. So a back and a lay of the same runner
+// trade when the lay's odds are at least the back's, at the resting order's
+// price. Backs queue lowest odds first and lays highest first: the best
+// price for whoever takes them.
 type Order struct {
-    ID       string `json:"id"`
-    MatchID  string `json:"match_id"`
-    TeamID   string `json:"team_id"`
-    UserID   string `json:"user_id"`
-    Side     string `json:"side"` // "bid" or "ask"
-    Price    float64 `json:"price"`
-    Quantity float64 `json:"quantity"`
+	ID       string `json:"id"`
+	MatchID  string `json:"match_id"`
+	TeamID   string `json:"team_id"`
+	UserID   string `json:"user_id"`
+	Side     string `json:"side"`     // "bid" backs TeamID, "ask" lays it
+	Price    Odds   `json:"price"`    // a back's lowest odds, a lay's highest
+	Quantity Money  `json:"quantity"` // the backer's stake; a lay risks Quantity·(Price-1)
+	Seq      uint64 `json:"seq"`      // engine acceptance sequence, used for time priority
+
+	TimeInForce string    `json:"time_in_force,omitempty"` // "GTC" (default), "IOC", "FOK" or "GTD"
+	ExpiresAt   time.Time `json:"expires_at,omitzero"`     // GTD only; defaults to match start
+
+	Type       string `json:"type,omitempty"`        // "limit" (default) or "market"
+	WorstPrice Odds   `json:"worst_price,omitempty"` // market only: worst acceptable odds; required for lays, 0 for no limit on backs
 }
@@ -0,0 +1,129 @@
+package orderbook
+
+import (
+	"maps"
+	"sort"
+)
+
+// Position is a user's book on one match: what they have backed and laid on
+// each runner, and what they make or lose whichever runner wins.
+type Position struct {
+	UserID  string           `json:"user_id"`
+	MatchID string           `json:"match_id"`
+	Staked  Money            `json:"staked"` // total at risk across the user's trades
+	Runners []RunnerPosition `json:"runners"`
+}
+
+// RunnerPosition is a user's net position on one runner.
+type RunnerPosition struct {
+	TeamID string `json:"team_id"`
+	Backed Money  `json:"backed"`  // backer's stake backed on the runner
+	Laid   Money  `json:"laid"`    // backer's stake laid against the runner
+	IfWins Money  `json:"if_wins"` // profit, or loss if negative, should the runner win
+}
+
+// position accumulates a user's trade legs in a match. won and lost are
+// kept per outcome so settlement can report them gross.
+type position struct {
+	staked Money
+	backed map[string]Money // runner → stake backed
+	laid   map[string]Money // runner → stake laid
+	won    map[string]Money // winner → paid on legs that win
+	lost   map[string]Money // winner → risk on legs that lose
+}
+
+func newPosition() *position {
+	return &position{
+		backed: make(map[string]Money),
+		laid:   make(map[string]Money),
+		won:    make(map[string]Money),
+		lost:   make(map[string]Money),
+	}
+}
+
+// add books one trade leg against every outcome of the match.
+func (p *position) add(leg TradeLeg, runners []string) {
+	p.staked += leg.Risk
+	if leg.Side == "bid" {
+		p.backed[leg.TeamID] += leg.Risk
+	} else {
+		p.laid[leg.TeamID] += leg.Win
+	}
+	for _, winner := range runners {
+		if leg.wins(winner) {
+			p.won[winner] += leg.Win
+		} else {
+			p.lost[winner] += leg.Risk
+		}
+	}
+}
+
+func (p *position) clone() *position {
+	c := newPosition()
+	c.staked = p.staked
+	maps.Copy(c.backed, p.backed)
+	maps.Copy(c.laid, p.laid)
+	maps.Copy(c.won, p.won)
+	maps.Copy(c.lost, p.lost)
+	return c
+}
+
+// ifWins is the position's profit or loss should winner win.
+func (p *position) ifWins(winner string) Money {
+	return p.won[winner] - p.lost[winner]
+}
+
+// positionOf returns the user's position in the market, creating it on the
+// user's first trade.
+func (m *market) positionOf(userID string) *position {
+	p, ok := m.positions[userID]
+	if !ok {
+		p = newPosition()
+		m.positions[userID] = p
+	}
+	return p
+}
+
+// view renders a user's position for callers outside the market.
+func (m *market) view(userID string, p *position) Position {
+	view := Position{
+		UserID:  userID,
+		MatchID: m.id,
+		Staked:  p.staked,
+		Runners: make([]RunnerPosition, 0, len(m.runners)),
+	}
+	for _, runner := range m.runners {
+		view.Runners = append(view.Runners, RunnerPosition{
+			TeamID: runner,
+			Backed: p.backed[runner],
+			Laid:   p.laid[runner],
+			IfWins: p.ifWins(runner),
+		})
+	}
+	return view
+}
+
+// GetPosition returns a user's position in a match and their profit or
+// loss under each outcome. A user with no trades has a flat position.
+func GetPosition(matchID, userID string) (Position, error) {
+	if userID == "" {
+		return Position{}, ErrMissingUser
+	}
+	m, err := lookupMarket(matchID)
+	if err != nil {
+		return Position{}, err
+	}
+	return execute(m, func() (Position, error) {
+		return m.view(userID, m.positionOrFlat(userID)), nil
+	})
+}
+
+// positionUsers returns every user with a position in the market, sorted.
+func (m *market) positionUsers() []string {
+	users := make([]string, 0, len(m.positions))
+	for userID := range m.positions {
+		users = append(users, userID)
+	}
+	sort.Strings(users)
+	return users
+}
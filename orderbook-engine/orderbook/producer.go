@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/IBM/sarama"
 )
@@ -11,57 +12,135 @@ import (
 var producer sarama.SyncProducer
 
 func init() {
-    // Configure Sarama producer
-    config := sarama.NewConfig()
-    config.Producer.RequiredAcks = sarama.WaitForAll          // Wait for all in-sync replicas to acknowledge
-    config.Producer.Retry.Max = 5                             // Retry up to 5 times
-    config.Producer.Return.Successes = true                  // Return success messages
-    config.Producer.Compression = sarama.CompressionSnappy   // Use Snappy compression for better performance
-    config.Version = sarama.V2_8_1_0                        // Set Kafka version
-
-    // Create a new Sarama producer
-    var err error
-    producer, err = sarama.NewSyncProducer([]string{"localhost:9092"}, config)
-    if err != nil {
-        log.Printf("Failed to start Sarama producer: %v", err)
-        return
-    }
+	// Configure Sarama producer
+	config := sarama.NewConfig()
+	config.Producer.RequiredAcks = sarama.WaitForAll       // Wait for all in-sync replicas to acknowledge
+	config.Producer.Retry.Max = 5                          // Retry up to 5 times
+	config.Producer.Return.Successes = true                // Return success messages
+	config.Producer.Compression = sarama.CompressionSnappy // Use Snappy compression for better performance
+	config.Version = sarama.V2_8_1_0                       // Set Kafka version
+
+	// Create a new Sarama producer
+	var err error
+	producer, err = sarama.NewSyncProducer([]string{"localhost:9092"}, config)
+	if err != nil {
+		log.Printf("Failed to start Sarama producer: %v", err)
+		return
+	}
 }
 
 // PublishMatchEvent publishes an order as a Kafka message
 func PublishMatchEvent(order Order) {
-    if producer == nil {
-        log.Println("Kafka producer not initialized")
-        return
-    }
-
-    payload, err := json.Marshal(order)
-    if err != nil {
-        log.Printf("Failed to marshal order: %v", err)
-        return
-    }
-    fmt.Println("Publishing event to Kafka:", string(payload))
-
-    // Create a Kafka message
-    message := &sarama.ProducerMessage{
-        Topic: "match.events",
-        Value: sarama.StringEncoder(payload),
-    }
-
-    // Send the message
-    partition, offset, err := producer.SendMessage(message)
-    if err != nil {
-        log.Println("Kafka publish failed:", err)
-    } else {
-        fmt.Printf("Message published to partition %d at offset %d\n", partition, offset)
-    }
+	publish("match.events", order)
+}
+
+// Order lifecycle event types.
+const (
+	OrderEventExpired            = "expired"
+	OrderEventRejected           = "rejected"
+	OrderEventSelfTradeCancelled = "self_trade_cancelled"
+	OrderEventCancelled          = "cancelled"
+)
+
+// OrderEvent reports a change to an order that did not come from a fill.
+type OrderEvent struct {
+	Type      string    `json:"type"`
+	Order     Order     `json:"order"`
+	Timestamp time.Time `json:"timestamp"`
+
+	RejectCode RejectCode `json:"reject_code,omitempty"`
+	Reason     string     `json:"reason,omitempty"`
+}
+
+// PublishOrderEvent publishes an order lifecycle event as a Kafka message
+func PublishOrderEvent(eventType string, order Order) {
+	publish("order.events", OrderEvent{Type: eventType, Order: order, Timestamp: now()})
+}
+
+// PublishOrderRejection publishes a rejected order with its reject code
+func PublishOrderRejection(order Order, code RejectCode, reason string) {
+	publish("order.events", OrderEvent{
+		Type:       OrderEventRejected,
+		Order:      order,
+		Timestamp:  now(),
+		RejectCode: code,
+		Reason:     reason,
+	})
+}
+
+// MarketEvent reports a market moving from one state to another.
+type MarketEvent struct {
+	MatchID   string      `json:"match_id"`
+	From      MarketState `json:"from"`
+	To        MarketState `json:"to"`
+	Reason    string      `json:"reason,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// PublishMarketEvent publishes a market state transition as a Kafka message
+func PublishMarketEvent(event MarketEvent) {
+	publish("market.events", event)
+}
+
+// Settlement event types.
+const (
+	SettlementEventSettled   = "settled"
+	SettlementEventVoided    = "voided"
+	SettlementEventResettled = "resettled"
+)
+
+// SettlementEvent reports a match being paid out. Voided and resettled
+// events carry the report they replace in Previous, so consumers can
+// reverse what they applied for it.
+type SettlementEvent struct {
+	Type      string            `json:"type"`
+	Report    SettlementReport  `json:"report"`
+	Previous  *SettlementReport `json:"previous,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// PublishSettlementEvent publishes a settlement as a Kafka message
+func PublishSettlementEvent(eventType string, report SettlementReport, previous *SettlementReport) {
+	publish("settlement.events", SettlementEvent{Type: eventType, Report: report, Previous: previous, Timestamp: now()})
+}
+
+func publish(topic string, event any) {
+	if replaying.Load() {
+		// Published when the command first ran
+		return
+	}
+	if producer == nil {
+		log.Println("Kafka producer not initialized")
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to marshal event: %v", err)
+		return
+	}
+	fmt.Println("Publishing event to Kafka:", string(payload))
+
+	// Create a Kafka message
+	message := &sarama.ProducerMessage{
+		Topic: topic,
+		Value: sarama.StringEncoder(payload),
+	}
+
+	// Send the message
+	partition, offset, err := producer.SendMessage(message)
+	if err != nil {
+		log.Println("Kafka publish failed:", err)
+	} else {
+		fmt.Printf("Message published to partition %d at offset %d\n", partition, offset)
+	}
 }
 
 // Close closes the Sarama producer
 func Close() {
-    if producer != nil {
-        if err := producer.Close(); err != nil {
-            log.Println("Failed to close Sarama producer:", err)
-        }
-    }
-}
\ No newline at end of file
+	if producer != nil {
+		if err := producer.Close(); err != nil {
+			log.Println("Failed to close Sarama producer:", err)
+		}
+	}
+}
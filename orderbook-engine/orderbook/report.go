@@ -0,0 +1,86 @@
+package orderbook
+
+// Order statuses reported in an ExecutionReport.
+const (
+	StatusNew             = "new"              // rested without any fill
+	StatusPartiallyFilled = "partially_filled" // some filled; any remainder rests or was discarded
+	StatusFilled          = "filled"           // completely filled
+	StatusRejected        = "rejected"         // not accepted into the market
+	StatusCancelled       = "cancelled"        // accepted but nothing filled or rested (IOC, FOK, market)
+	StatusExpired         = "expired"          // GTD order already past its expiry
+)
+
+// Match types reported on a Fill.
+const (
+	MatchSameTeam  = "same_team"  // bid against ask in the same team's book
+	MatchCrossTeam = "cross_team" // against the same side of every other runner's book
+)
+
+// Fill is one execution against resting orders. A same-team fill has one
+// counterparty; a cross fill has one per other runner, listed in
+// CounterpartyOrderIDs with the first repeated in CounterpartyOrderID.
+type Fill struct {
+	CounterpartyOrderID  string   `json:"counterparty_order_id"`
+	CounterpartyOrderIDs []string `json:"counterparty_order_ids,omitempty"`
+	Price                Odds     `json:"price"`
+	Quantity             Money    `json:"quantity"`            // backer's stake
+	Liability            Money    `json:"liability,omitempty"` // what the order risks, for lays
+	MatchType            string   `json:"match_type"`
+}
+
+// ExecutionReport is the outcome of placing an order.
+type ExecutionReport struct {
+	OrderID string `json:"order_id"`
+	Status  string `json:"status"`
+	Fills   []Fill `json:"fills"`
+	Resting Money  `json:"resting_quantity"` // quantity left in the book
+
+	// SelfTradePrevented is the quantity removed from the order by
+	// self-trade prevention instead of being filled or rested.
+	SelfTradePrevented Money `json:"self_trade_prevented,omitempty"`
+
+	RejectCode RejectCode `json:"reject_code,omitempty"`
+	Reason     string     `json:"reason,omitempty"`
+
+	incomingCancelled bool // matching stopped and nothing rests
+}
+
+// FilledQuantity returns the total quantity across all fills.
+func (r *ExecutionReport) FilledQuantity() Money {
+	var total Money
+	for _, fill := range r.Fills {
+		total += fill.Quantity
+	}
+	return total
+}
+
+// finish sets the status from how much of quantity was filled or rested.
+func (r *ExecutionReport) finish(quantity Money) {
+	filled := r.FilledQuantity()
+	switch {
+	case filled == quantity:
+		r.Status = StatusFilled
+	case filled > 0:
+		r.Status = StatusPartiallyFilled
+	case r.Resting > 0:
+		r.Status = StatusNew
+	default:
+		r.Status = StatusCancelled
+	}
+}
+
+// cancelIncoming records that self-trade prevention cancelled the
+// remaining quantity of the incoming order.
+func (r *ExecutionReport) cancelIncoming(remaining Money) {
+	r.SelfTradePrevented += remaining
+	r.incomingCancelled = true
+	r.Reason = "cancelled by self-trade prevention"
+}
+
+// rejectFill records that a fill could not be recorded. Matching stops and
+// the rest of the incoming order is cancelled, rather than left resting
+// across the order it failed to trade with.
+func (r *ExecutionReport) rejectFill(err error) {
+	r.incomingCancelled = true
+	r.Reason = "fill rejected: " + err.Error()
+}
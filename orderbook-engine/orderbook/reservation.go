@@ -0,0 +1,122 @@
+package orderbook
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+// DefaultMaxExposure applies to markets that never set a limit: none.
+const DefaultMaxExposure Money = 0
+
+// SetMaxExposure caps how much any one user may have at risk in a match,
+// counting both matched trades and funds reserved for resting orders. Zero
+// removes the cap. It applies to orders placed or amended after the call.
+func SetMaxExposure(matchID string, limit Money) error {
+	if limit < 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidAmount, limit)
+	}
+	m, err := lookupMarket(matchID)
+	if err != nil {
+		return err
+	}
+	cmd := Command{Type: CommandMaxExposure, MatchID: m.id, Amount: limit}
+	_, err = marketCommand(m, cmd, func() (struct{}, error) {
+		m.maxExposure = limit
+		log.Printf("Match %s: max exposure per user %s", m.id, limit)
+		return struct{}{}, nil
+	})
+	return err
+}
+
+// maxRisk is the most the order could lose if all of its quantity were
+// matched: a back's stake, or a lay's liability at its worst odds. Market
+// lays must have a worst price, so a lay's liability is always bounded.
+func (o *Order) maxRisk() Money {
+	if o.Side == "bid" {
+		return o.Quantity
+	}
+	limit := o.Price
+	if o.Type == OrderTypeMarket {
+		limit = o.WorstPrice
+	}
+	return o.Quantity.Liability(limit)
+}
+
+// reserve tops up the funds held for order to its maxRisk, moving them out
+// of the user's available balance. Must run on the market goroutine.
+func (m *market) reserve(order *Order) error {
+	need := order.maxRisk() - m.reserved[order.ID]
+	if need <= 0 {
+		return nil
+	}
+	exposure := m.exposure[order.UserID]
+	if m.maxExposure > 0 && exposure+need > m.maxExposure {
+		return fmt.Errorf("%w: %s at risk of %s, order needs %s",
+			ErrExposureLimit, exposure, m.maxExposure, need)
+	}
+	if err := reserveFunds(m.cmd, order.UserID, m.id, order.ID, need); err != nil {
+		return err
+	}
+	m.reserved[order.ID] += need
+	m.exposure[order.UserID] = exposure + need
+	return nil
+}
+
+// release returns whatever is held for order beyond what it could still
+// lose resting in the book: everything, once it has left the book. Must run
+// on the market goroutine.
+func (m *market) release(order *Order) {
+	held := m.reserved[order.ID]
+	var need Money
+	if m.books[order.TeamID].orders[order.ID] == order {
+		need = order.maxRisk()
+	}
+	if held > need {
+		releaseFunds(m.cmd, order.UserID, m.id, order.ID, held-need)
+		m.exposure[order.UserID] -= held - need
+		m.reserved[order.ID] = need
+	}
+	if need == 0 {
+		delete(m.reserved, order.ID)
+	}
+}
+
+// consume turns the funds reserved for each leg's order into the trade's
+// risk. If any order has less reserved than its leg risks, nothing is
+// consumed and the fill must be rejected, so a reservation never goes
+// negative. The ledger side of the move is the trade's fill entry.
+func (m *market) consume(legs []TradeLeg) error {
+	for _, leg := range legs {
+		if held := m.reserved[leg.OrderID]; held < leg.Risk {
+			return fmt.Errorf("%w: order %s risks %s with %s reserved",
+				ErrShortReservation, leg.OrderID, leg.Risk, held)
+		}
+	}
+	for _, leg := range legs {
+		m.reserved[leg.OrderID] -= leg.Risk
+	}
+	return nil
+}
+
+// unconsume puts back what consume took, for a fill the ledger refused.
+func (m *market) unconsume(legs []TradeLeg) {
+	for _, leg := range legs {
+		m.reserved[leg.OrderID] += leg.Risk
+	}
+}
+
+// releaseExposure forgets every user's exposure once the match has been
+// paid out or refunded: its resting orders were cancelled when it closed,
+// and none of its trades is at risk any more.
+func (m *market) releaseExposure() {
+	clear(m.exposure)
+}
+
+// rejectCodeFor maps a failed reservation onto the order's reject code.
+func rejectCodeFor(err error) RejectCode {
+	if errors.Is(err, ErrExposureLimit) {
+		return RejectExposureLimit
+	}
+	return RejectInsufficientFunds
+}
@@ -0,0 +1,107 @@
+package orderbook
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestShortReservationRejectsFill takes funds from a resting lay's
+// reservation and checks that a back which would fill it is refused
+// without touching the lay, the trades or the ledger.
+func TestShortReservationRejectsFill(t *testing.T) {
+	matchID, user := openTestMarket(t, []string{"a", "b"}, "backer", "layer")
+	place(t, Order{ID: "lay", MatchID: matchID, TeamID: "a", UserID: user("layer"), Side: "ask", Price: 3_00, Quantity: 10_00})
+
+	m, err := lookupMarket(matchID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	execute(m, func() (struct{}, error) {
+		m.reserved["lay"] = 5_00 // the lay's liability is 20.00
+		return struct{}{}, nil
+	})
+
+	report := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("backer"), Side: "bid", Price: 3_00, Quantity: 10_00})
+	if report.Status != StatusCancelled || len(report.Fills) != 0 || report.Resting != 0 {
+		t.Fatalf("back: %+v", report)
+	}
+	if !strings.Contains(report.Reason, ErrShortReservation.Error()) {
+		t.Errorf("reason %q", report.Reason)
+	}
+	if got := resting(t, matchID, "lay"); got != 10_00 {
+		t.Errorf("lay has %s resting, want 10.00", got)
+	}
+	if trades := matchTrades(t, matchID); len(trades) != 0 {
+		t.Errorf("%d trades recorded", len(trades))
+	}
+	if got := balanceOf(escrowAccount(matchID)); got != 0 {
+		t.Errorf("escrow holds %s", got)
+	}
+	reserved, _ := execute(m, func() (Money, error) { return m.reserved["lay"], nil })
+	if reserved != 5_00 {
+		t.Errorf("lay has %s reserved, want 5.00", reserved)
+	}
+	checkLedgerBalances(t)
+}
+
+// TestExposureReleased checks that nothing counts against a user's
+// exposure once a match is settled or voided.
+func TestExposureReleased(t *testing.T) {
+	for _, end := range []string{"settle", "void"} {
+		t.Run(end, func(t *testing.T) {
+			matchID, user := openTestMarket(t, []string{"a", "b"}, "backer", "layer")
+			place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("layer"), Side: "ask", Price: 2_00, Quantity: 10_00})
+			place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("backer"), Side: "bid", Price: 2_00, Quantity: 6_00})
+
+			var err error
+			if end == "settle" {
+				_, err = SettleMatch(matchID, "a")
+			} else {
+				_, err = VoidMatch(matchID, "abandoned")
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			m, err := lookupMarket(matchID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			users, _ := execute(m, func() (int, error) { return len(m.exposure), nil })
+			if users != 0 {
+				t.Errorf("%d users still exposed after %s", users, end)
+			}
+			checkLedgerBalances(t)
+		})
+	}
+}
+
+// TestMarketLayNeedsWorstPrice checks that a market lay must bound its
+// liability, and that the bound is what it reserves.
+func TestMarketLayNeedsWorstPrice(t *testing.T) {
+	matchID, user := openTestMarket(t, []string{"a", "b"}, "backer", "layer")
+	place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("backer"), Side: "bid", Price: 3_00, Quantity: 4_00})
+
+	unbounded := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("layer"), Side: "ask", Type: OrderTypeMarket, Quantity: 10_00})
+	if unbounded.RejectCode != RejectMissingWorstPrice {
+		t.Fatalf("unbounded market lay: %+v", unbounded)
+	}
+
+	// 10.00 at up to 3.00 reserves 20.00; 4.00 fills and the rest is released.
+	bounded := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("layer"), Side: "ask", Type: OrderTypeMarket, WorstPrice: 3_00, Quantity: 10_00})
+	if bounded.FilledQuantity() != 4_00 || bounded.Resting != 0 {
+		t.Fatalf("bounded market lay: %+v", bounded)
+	}
+	if got := balanceOf(reservedAccount(user("layer"))); got != 0 {
+		t.Errorf("layer has %s reserved", got)
+	}
+	if got, want := balanceOf(availableAccount(user("layer"))), Money(10_000_00-8_00); got != want {
+		t.Errorf("layer has %s available, want %s", got, want)
+	}
+
+	// Market backs still need no bound.
+	place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("layer"), Side: "ask", Price: 3_00, Quantity: 2_00})
+	back := place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("backer"), Side: "bid", Type: OrderTypeMarket, Quantity: 2_00})
+	if back.Status != StatusFilled {
+		t.Errorf("market back: %+v", back)
+	}
+}
@@ -0,0 +1,96 @@
+package orderbook
+
+import "log"
+
+// SelfTradePolicy decides what happens when an incoming order would trade
+// with a resting order from the same user.
+type SelfTradePolicy string
+
+const (
+	// SelfTradeCancelResting removes the resting order and keeps matching.
+	SelfTradeCancelResting SelfTradePolicy = "cancel_resting"
+	// SelfTradeCancelIncoming cancels whatever is left of the incoming order.
+	SelfTradeCancelIncoming SelfTradePolicy = "cancel_incoming"
+	// SelfTradeCancelBoth removes the resting order and cancels the incoming one.
+	SelfTradeCancelBoth SelfTradePolicy = "cancel_both"
+	// SelfTradeDecrementBoth reduces both orders by the smaller quantity
+	// without trading.
+	SelfTradeDecrementBoth SelfTradePolicy = "decrement_both"
+)
+
+// DefaultSelfTradePolicy applies to markets that never set one.
+const DefaultSelfTradePolicy = SelfTradeCancelResting
+
+// Valid reports whether p is one of the known policies.
+func (p SelfTradePolicy) Valid() bool {
+	switch p {
+	case SelfTradeCancelResting, SelfTradeCancelIncoming, SelfTradeCancelBoth, SelfTradeDecrementBoth:
+		return true
+	}
+	return false
+}
+
+// SetSelfTradePolicy changes the self-trade prevention policy of a match.
+// It applies to orders placed or amended after the call.
+func SetSelfTradePolicy(matchID string, policy SelfTradePolicy) error {
+	if !policy.Valid() {
+		return ErrInvalidSelfTradePolicy
+	}
+	m, err := lookupMarket(matchID)
+	if err != nil {
+		return err
+	}
+	cmd := Command{Type: CommandSelfTradePolicy, MatchID: m.id, Policy: string(policy)}
+	_, err = marketCommand(m, cmd, func() (struct{}, error) {
+		m.selfTradePolicy = policy
+		log.Printf("Match %s: self-trade prevention set to %s", m.id, policy)
+		return struct{}{}, nil
+	})
+	return err
+}
+
+// preventSelfTrade applies the market's policy to an incoming order that
+// would trade with resting, an order from the same user in book.
+// incomingQty and restingQty are what each order would have traded: the
+// same stake when they meet on one runner, and each order's own leg when
+// they meet in a cross. It returns the incoming quantity still to be
+// matched; when the incoming order is cancelled the report is marked so
+// matching stops and nothing rests.
+func (m *market) preventSelfTrade(incoming Order, resting *Order, book *OrderBook, incomingQty, restingQty, remainingQty Money, report *ExecutionReport) Money {
+	log.Printf("Self-trade: order %s would match %s for user %s (%s)",
+		incoming.ID, resting.ID, incoming.UserID, m.selfTradePolicy)
+
+	switch m.selfTradePolicy {
+	case SelfTradeCancelIncoming:
+		report.cancelIncoming(remainingQty)
+		return 0
+	case SelfTradeCancelBoth:
+		m.cancelSelfTradeResting(book, resting)
+		report.cancelIncoming(remainingQty)
+		return 0
+	case SelfTradeDecrementBoth:
+		resting.Quantity -= restingQty
+		if resting.Quantity <= 0 {
+			book.remove(resting)
+		}
+		m.release(resting)
+		report.SelfTradePrevented += incomingQty
+		return remainingQty - incomingQty
+	default:
+		m.cancelSelfTradeResting(book, resting)
+		return remainingQty
+	}
+}
+
+func (m *market) cancelSelfTradeResting(book *OrderBook, resting *Order) {
+	book.remove(resting)
+	m.release(resting)
+	PublishOrderEvent(OrderEventSelfTradeCancelled, *resting)
+}
+
+// selfTradeStopsFill reports whether meeting one of the user's own
+// resting orders stops an incoming order from filling past it. Only
+// cancelling the resting order lets matching continue at full size.
+func (m *market) selfTradeStopsFill() bool {
+	return m.selfTradePolicy != SelfTradeCancelResting
+}
@@ -0,0 +1,29 @@
+package orderbook
+
+import "testing"
+
+// TestDecrementBothInCross meets a back with the same user's back on
+// another runner of a three-way cross. The legs are sized at different
+// odds, so each order must lose what its own leg would have traded, not
+// the same stake.
+func TestDecrementBothInCross(t *testing.T) {
+	matchID, user := openTestMarket(t, []string{"a", "b", "c"}, "self", "other")
+	if err := SetSelfTradePolicy(matchID, SelfTradeDecrementBoth); err != nil {
+		t.Fatal(err)
+	}
+	place(t, Order{ID: "self-b", MatchID: matchID, TeamID: "b", UserID: user("self"), Side: "bid", Price: 4_00, Quantity: 20_00})
+	place(t, Order{ID: "other-c", MatchID: matchID, TeamID: "c", UserID: user("other"), Side: "bid", Price: 4_00, Quantity: 5_00})
+
+	// At 2.00, 4.00 and 4.00 a pot of 20.00 takes 10.00 on a and 5.00 each
+	// on b and c.
+	report := place(t, Order{ID: "self-a", MatchID: matchID, TeamID: "a", UserID: user("self"), Side: "bid", Price: 2_00, Quantity: 10_00})
+	if len(report.Fills) != 0 || report.SelfTradePrevented != 10_00 {
+		t.Fatalf("incoming: %+v", report)
+	}
+	if got := resting(t, matchID, "self-b"); got != 15_00 {
+		t.Errorf("self-b rests %s, want 15.00", got)
+	}
+	if got := resting(t, matchID, "other-c"); got != 5_00 {
+		t.Errorf("other-c rests %s, want 5.00", got)
+	}
+}
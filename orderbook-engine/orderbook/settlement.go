@@ -0,0 +1,233 @@
+package orderbook
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// UserSettlement is one user's result for a settled match.
+type UserSettlement struct {
+	UserID string `json:"user_id"`
+	Staked Money  `json:"staked"` // total at risk across the user's trades
+	Won    Money  `json:"won"`    // paid on bets that won
+	Lost   Money  `json:"lost"`   // lost on bets that lost
+	Net    Money  `json:"net"`    // Won - Lost; zero when voided
+}
+
+// SettlementReport is the outcome of settling a match.
+type SettlementReport struct {
+	MatchID       string           `json:"match_id"`
+	WinningTeamID string           `json:"winning_team_id"`
+	Users         []UserSettlement `json:"users"` // sorted by user ID
+	HouseNet      Money            `json:"house_net"`
+	Voided        bool             `json:"voided"` // every stake refunded, no winner
+	SettledAt     time.Time        `json:"settled_at"`
+}
+
+// SettleMatch declares winningTeamID the winner of a match and pays out
+// every trade. Resting orders are cancelled and the market ends SETTLED.
+// Settling again with the same winner returns the original report.
+func SettleMatch(matchID, winningTeamID string) (SettlementReport, error) {
+	m, err := lookupMarket(matchID)
+	if err != nil {
+		return SettlementReport{}, err
+	}
+	cmd := Command{Type: CommandSettle, MatchID: m.id, TeamID: winningTeamID}
+	return marketCommand(m, cmd, func() (SettlementReport, error) {
+		return m.settle(winningTeamID)
+	})
+}
+
+func (m *market) settle(winner string) (SettlementReport, error) {
+	if m.state == MarketVoided {
+		return SettlementReport{}, ErrMarketVoided
+	}
+	if m.settlement != nil {
+		if m.settlement.WinningTeamID != winner {
+			return SettlementReport{}, fmt.Errorf("%w with winner %s", ErrAlreadySettled, m.settlement.WinningTeamID)
+		}
+		return *m.settlement, nil
+	}
+	if _, ok := m.books[winner]; !ok {
+		return SettlementReport{}, fmt.Errorf("%w: %q", ErrUnknownRunner, winner)
+	}
+	if m.state != MarketClosed {
+		// Closing cancels whatever is still resting.
+		if _, err := m.transition(MarketClosed, "settling"); err != nil {
+			return SettlementReport{}, err
+		}
+	}
+
+	report := m.settlementReport(winner)
+	posted, err := post(m.cmd, settlementEntry(report))
+	if err != nil {
+		return SettlementReport{}, err
+	}
+	m.posted = posted
+	m.settlement = &report
+	m.releaseExposure()
+	if _, err := m.transition(MarketSettled, "winner "+winner); err != nil {
+		return SettlementReport{}, err
+	}
+
+	log.Printf("Match %s settled: %s won, %d users, house net %s",
+		m.id, winner, len(report.Users), report.HouseNet)
+	PublishSettlementEvent(SettlementEventSettled, report, nil)
+	return report, nil
+}
+
+// ResettleMatch corrects the winner of a settled match. The original
+// settlement's ledger entries are reversed and the trades paid out again
+// for winningTeamID. Resettling with the current winner changes nothing.
+func ResettleMatch(matchID, winningTeamID string) (SettlementReport, error) {
+	m, err := lookupMarket(matchID)
+	if err != nil {
+		return SettlementReport{}, err
+	}
+	cmd := Command{Type: CommandResettle, MatchID: m.id, TeamID: winningTeamID}
+	return marketCommand(m, cmd, func() (SettlementReport, error) {
+		return m.resettle(winningTeamID)
+	})
+}
+
+func (m *market) resettle(winner string) (SettlementReport, error) {
+	if m.state != MarketSettled {
+		return SettlementReport{}, fmt.Errorf("%w: market is %s", ErrNotSettled, m.state)
+	}
+	if _, ok := m.books[winner]; !ok {
+		return SettlementReport{}, fmt.Errorf("%w: %q", ErrUnknownRunner, winner)
+	}
+	if m.settlement.WinningTeamID == winner {
+		return *m.settlement, nil
+	}
+
+	previous := m.settlement
+	report := m.settlementReport(winner)
+	posted, err := post(m.cmd, append(reversals(m.posted), settlementEntry(report))...)
+	if err != nil {
+		return SettlementReport{}, err
+	}
+	m.posted = posted[len(posted)-1:]
+	m.settlement = &report
+
+	log.Printf("Match %s resettled: %s won, previously %s", m.id, winner, previous.WinningTeamID)
+	PublishSettlementEvent(SettlementEventResettled, report, previous)
+	return report, nil
+}
+
+// VoidMatch calls a match off and refunds every stake. Resting orders are
+// cancelled; if the match was already settled its ledger entries are
+// reversed. Voiding again returns the original report.
+func VoidMatch(matchID, reason string) (SettlementReport, error) {
+	m, err := lookupMarket(matchID)
+	if err != nil {
+		return SettlementReport{}, err
+	}
+	cmd := Command{Type: CommandVoid, MatchID: m.id, Reason: reason}
+	return marketCommand(m, cmd, func() (SettlementReport, error) {
+		return m.void(reason)
+	})
+}
+
+func (m *market) void(reason string) (SettlementReport, error) {
+	if m.state == MarketVoided {
+		return *m.settlement, nil
+	}
+	previous := m.settlement
+	if _, err := m.transition(MarketVoided, reason); err != nil {
+		return SettlementReport{}, err
+	}
+
+	// Undo any payout first, so every stake is back in escrow to refund.
+	report := m.voidReport()
+	if _, err := post(m.cmd, append(reversals(m.posted), refundEntry(report))...); err != nil {
+		return SettlementReport{}, err
+	}
+	m.posted = nil
+	m.settlement = &report
+	m.releaseExposure()
+
+	log.Printf("Match %s voided: %d users refunded", m.id, len(report.Users))
+	PublishSettlementEvent(SettlementEventVoided, report, previous)
+	return report, nil
+}
+
+// voidReport lists what each user had staked, all of it refunded.
+func (m *market) voidReport() SettlementReport {
+	report := SettlementReport{
+		MatchID:   m.id,
+		Users:     make([]UserSettlement, 0, len(m.positions)),
+		Voided:    true,
+		SettledAt: m.cmd.at,
+	}
+	for _, userID := range m.positionUsers() {
+		report.Users = append(report.Users, UserSettlement{UserID: userID, Staked: m.positions[userID].staked})
+	}
+	return report
+}
+
+// settlementReport pays out each user's position for winner, the same
+// figures GetPosition shows for that outcome.
+func (m *market) settlementReport(winner string) SettlementReport {
+	report := SettlementReport{
+		MatchID:       m.id,
+		WinningTeamID: winner,
+		Users:         make([]UserSettlement, 0, len(m.positions)),
+		SettledAt:     m.cmd.at,
+	}
+	for _, userID := range m.positionUsers() {
+		p := m.positions[userID]
+		user := UserSettlement{
+			UserID: userID,
+			Staked: p.staked,
+			Won:    p.won[winner],
+			Lost:   p.lost[winner],
+			Net:    p.ifWins(winner),
+		}
+		report.HouseNet -= user.Net
+		report.Users = append(report.Users, user)
+	}
+	return report
+}
+
+// settlementEntry pays each user their stake back plus their net from
+// escrow and books the difference to the house.
+func settlementEntry(report SettlementReport) JournalEntry {
+	var postings []Posting
+	var escrow Money
+	for _, user := range report.Users {
+		postings = addPosting(postings, availableAccount(user.UserID), user.Staked+user.Net)
+		escrow += user.Staked
+	}
+	postings = addPosting(postings, HouseAccount, report.HouseNet)
+	return JournalEntry{
+		Kind:     EntrySettlement,
+		MatchID:  report.MatchID,
+		Postings: addPosting(postings, escrowAccount(report.MatchID), -escrow),
+	}
+}
+
+// refundEntry returns every user's stake from escrow.
+func refundEntry(report SettlementReport) JournalEntry {
+	var postings []Posting
+	var escrow Money
+	for _, user := range report.Users {
+		postings = addPosting(postings, availableAccount(user.UserID), user.Staked)
+		escrow += user.Staked
+	}
+	return JournalEntry{
+		Kind:     EntryRefund,
+		MatchID:  report.MatchID,
+		Postings: addPosting(postings, escrowAccount(report.MatchID), -escrow),
+	}
+}
+
+// reversals cancels each of entries.
+func reversals(entries []JournalEntry) []JournalEntry {
+	out := make([]JournalEntry, 0, len(entries))
+	for _, entry := range entries {
+		out = append(out, reversal(entry))
+	}
+	return out
+}
@@ -0,0 +1,188 @@
+package orderbook
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// settlementMarket trades a back of a at 3.00 and a back of b at 2.00,
+// both laid by the same user, and leaves a lay of a resting.
+//
+//	          backer  layer  other
+//	staked       10     30     10
+//	a wins      +20    -10    -10
+//	b wins      -10      0    +10
+func settlementMarket(t *testing.T) (string, func(string) string) {
+	t.Helper()
+	matchID, user := openTestMarket(t, []string{"a", "b"}, "backer", "layer", "other")
+	place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("layer"), Side: "ask", Price: 3_00, Quantity: 10_00})
+	place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("backer"), Side: "bid", Price: 3_00, Quantity: 10_00})
+	place(t, Order{MatchID: matchID, TeamID: "b", UserID: user("layer"), Side: "ask", Price: 2_00, Quantity: 10_00})
+	place(t, Order{MatchID: matchID, TeamID: "b", UserID: user("other"), Side: "bid", Price: 2_00, Quantity: 10_00})
+	place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("layer"), Side: "ask", Price: 4_00, Quantity: 5_00})
+	return matchID, user
+}
+
+// checkWallets checks each user's available balance, that nothing is left
+// reserved or in the match's escrow, and that the ledger balances.
+func checkWallets(t *testing.T, matchID string, user func(string) string, available map[string]Money) {
+	t.Helper()
+	for name, want := range available {
+		if got := balanceOf(availableAccount(user(name))); got != want {
+			t.Errorf("%s has %s available, want %s", name, got, want)
+		}
+		if got := balanceOf(reservedAccount(user(name))); got != 0 {
+			t.Errorf("%s has %s still reserved", name, got)
+		}
+	}
+	if got := balanceOf(escrowAccount(matchID)); got != 0 {
+		t.Errorf("escrow holds %s", got)
+	}
+	checkLedgerBalances(t)
+}
+
+func TestSettleMatch(t *testing.T) {
+	tests := []struct {
+		winner    string
+		users     []UserSettlement
+		available map[string]Money
+	}{
+		{
+			winner: "a",
+			users: []UserSettlement{
+				{UserID: "backer", Staked: 10_00, Won: 20_00, Net: 20_00},
+				{UserID: "layer", Staked: 30_00, Won: 10_00, Lost: 20_00, Net: -10_00},
+				{UserID: "other", Staked: 10_00, Lost: 10_00, Net: -10_00},
+			},
+			available: map[string]Money{"backer": 10_020_00, "layer": 9_990_00, "other": 9_990_00},
+		},
+		{
+			winner: "b",
+			users: []UserSettlement{
+				{UserID: "backer", Staked: 10_00, Lost: 10_00, Net: -10_00},
+				{UserID: "layer", Staked: 30_00, Won: 10_00, Lost: 10_00},
+				{UserID: "other", Staked: 10_00, Won: 10_00, Net: 10_00},
+			},
+			available: map[string]Money{"backer": 9_990_00, "layer": 10_000_00, "other": 10_010_00},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.winner+" wins", func(t *testing.T) {
+			matchID, user := settlementMarket(t)
+			report, err := SettleMatch(matchID, tt.winner)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.WinningTeamID != tt.winner || report.HouseNet != 0 || report.Voided {
+				t.Errorf("report %+v", report)
+			}
+			want := make([]UserSettlement, len(tt.users))
+			for i, u := range tt.users {
+				u.UserID = user(u.UserID)
+				want[i] = u
+			}
+			if !reflect.DeepEqual(report.Users, want) {
+				t.Errorf("users\n got %+v\nwant %+v", report.Users, want)
+			}
+			checkWallets(t, matchID, user, tt.available)
+			if state, _ := GetMarketState(matchID); state != MarketSettled {
+				t.Errorf("market is %s", state)
+			}
+
+			// Settling again with the same winner changes nothing; with
+			// another winner it is refused.
+			again, err := SettleMatch(matchID, tt.winner)
+			if err != nil || !reflect.DeepEqual(again, report) {
+				t.Errorf("settling again: %+v, %v", again, err)
+			}
+			other := "a"
+			if tt.winner == "a" {
+				other = "b"
+			}
+			if _, err := SettleMatch(matchID, other); !errors.Is(err, ErrAlreadySettled) {
+				t.Errorf("settling with another winner: %v", err)
+			}
+			checkWallets(t, matchID, user, tt.available)
+		})
+	}
+}
+
+func TestVoidMatch(t *testing.T) {
+	refunded := map[string]Money{"backer": 10_000_00, "layer": 10_000_00, "other": 10_000_00}
+	for _, settled := range []bool{false, true} {
+		name := "open"
+		if settled {
+			name = "settled"
+		}
+		t.Run(name, func(t *testing.T) {
+			matchID, user := settlementMarket(t)
+			// Before: stakes in escrow and the resting lay's liability reserved.
+			if got, want := balanceOf(escrowAccount(matchID)), Money(50_00); got != want {
+				t.Fatalf("escrow holds %s, want %s", got, want)
+			}
+			if got, want := balanceOf(reservedAccount(user("layer"))), Money(15_00); got != want {
+				t.Fatalf("layer has %s reserved, want %s", got, want)
+			}
+			if settled {
+				if _, err := SettleMatch(matchID, "a"); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			report, err := VoidMatch(matchID, "abandoned")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !report.Voided || report.WinningTeamID != "" || len(report.Users) != 3 {
+				t.Errorf("report %+v", report)
+			}
+			for _, u := range report.Users {
+				if u.Net != 0 || u.Won != 0 || u.Lost != 0 {
+					t.Errorf("voided user %+v", u)
+				}
+			}
+			checkWallets(t, matchID, user, refunded)
+
+			if again, err := VoidMatch(matchID, "again"); err != nil || !reflect.DeepEqual(again, report) {
+				t.Errorf("voiding again: %+v, %v", again, err)
+			}
+			if _, err := SettleMatch(matchID, "a"); !errors.Is(err, ErrMarketVoided) {
+				t.Errorf("settling a voided match: %v", err)
+			}
+			checkWallets(t, matchID, user, refunded)
+		})
+	}
+}
+
+func TestResettleMatch(t *testing.T) {
+	matchID, user := settlementMarket(t)
+	if _, err := ResettleMatch(matchID, "b"); !errors.Is(err, ErrNotSettled) {
+		t.Fatalf("resettling an open match: %v", err)
+	}
+	if _, err := SettleMatch(matchID, "a"); err != nil {
+		t.Fatal(err)
+	}
+	checkWallets(t, matchID, user, map[string]Money{"backer": 10_020_00, "layer": 9_990_00, "other": 9_990_00})
+
+	report, err := ResettleMatch(matchID, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.WinningTeamID != "b" {
+		t.Errorf("report %+v", report)
+	}
+	// Every wallet ends as if b had won in the first place.
+	checkWallets(t, matchID, user, map[string]Money{"backer": 9_990_00, "layer": 10_000_00, "other": 10_010_00})
+
+	if again, err := ResettleMatch(matchID, "b"); err != nil || !reflect.DeepEqual(again, report) {
+		t.Errorf("resettling with the same winner: %+v, %v", again, err)
+	}
+	checkWallets(t, matchID, user, map[string]Money{"backer": 9_990_00, "layer": 10_000_00, "other": 10_010_00})
+
+	// Back to a: the first settlement's figures again.
+	if _, err := ResettleMatch(matchID, "a"); err != nil {
+		t.Fatal(err)
+	}
+	checkWallets(t, matchID, user, map[string]Money{"backer": 10_020_00, "layer": 9_990_00, "other": 9_990_00})
+}
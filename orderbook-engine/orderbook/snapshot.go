@@ -0,0 +1,316 @@
+package orderbook
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SnapshotVersion is the format WriteSnapshot produces. A snapshot of any
+// other version is refused rather than partly restored.
+const SnapshotVersion = 2
+
+// snapshotsKept is how many snapshot files WriteSnapshot leaves in its
+// directory; older ones are deleted.
+const snapshotsKept = 5
+
+// Snapshot is the whole engine at one instant: every market, the wallet
+// ledger and the last command applied, which new orders, trades and
+// commands are numbered after.
+type Snapshot struct {
+	Version    int              `json:"version"`
+	TakenAt    time.Time        `json:"taken_at"`
+	CommandSeq uint64           `json:"command_seq"` // last command log record included
+	Ledger     []JournalEntry   `json:"ledger"`
+	Markets    []MarketSnapshot `json:"markets"` // sorted by match ID
+}
+
+// MarketSnapshot is one market's state. Positions are not stored; they are
+// rebuilt from the trades.
+type MarketSnapshot struct {
+	MatchID         string            `json:"match_id"`
+	Runners         []string          `json:"runners"`
+	Start           time.Time         `json:"start"`
+	State           MarketState       `json:"state"`
+	SelfTradePolicy SelfTradePolicy   `json:"self_trade_policy"`
+	SurplusPolicy   SurplusPolicy     `json:"surplus_policy"`
+	MaxExposure     Money             `json:"max_exposure"`
+	Orders          []Order           `json:"orders"` // resting, by Seq
+	Trades          []Trade           `json:"trades"`
+	OrderIDs        []string          `json:"order_ids"` // every ID accepted, sorted
+	Reserved        map[string]Money  `json:"reserved"`  // orderID → funds held
+	Exposure        map[string]Money  `json:"exposure"`  // userID → reserved plus matched risk
+	Settlement      *SettlementReport `json:"settlement,omitempty"`
+	Posted          []JournalEntry    `json:"posted,omitempty"`
+}
+
+// TakeSnapshot captures every market at the same instant, between two
+// commands. It stops admitting commands, lets every market apply those
+// already admitted and holds the markets still while the copy is made.
+func TakeSnapshot() Snapshot {
+	cmdLog.inFlight.Lock()
+	defer cmdLog.inFlight.Unlock()
+	cmdLog.admitting.Lock()
+	defer cmdLog.admitting.Unlock()
+
+	all := allMarkets()
+	var paused sync.WaitGroup
+	resume := make(chan struct{})
+	defer close(resume)
+	for _, m := range all {
+		paused.Add(1)
+		m.commands <- func() {
+			paused.Done()
+			<-resume
+		}
+	}
+	paused.Wait()
+
+	ledger.Lock()
+	defer ledger.Unlock()
+	s := Snapshot{
+		Version:    SnapshotVersion,
+		TakenAt:    now(),
+		CommandSeq: cmdLog.seq,
+		Ledger:     slices.Clone(ledger.entries),
+		Markets:    make([]MarketSnapshot, 0, len(all)),
+	}
+	for _, m := range all {
+		s.Markets = append(s.Markets, m.snapshot())
+	}
+	sort.Slice(s.Markets, func(i, j int) bool { return s.Markets[i].MatchID < s.Markets[j].MatchID })
+	return s
+}
+
+// snapshot copies the market's state; the caller has paused the market.
+func (m *market) snapshot() MarketSnapshot {
+	s := MarketSnapshot{
+		MatchID:         m.id,
+		Runners:         slices.Clone(m.runners),
+		Start:           m.start,
+		State:           m.state,
+		SelfTradePolicy: m.selfTradePolicy,
+		SurplusPolicy:   m.surplusPolicy,
+		MaxExposure:     m.maxExposure,
+		Trades:          slices.Clone(m.trades),
+		Reserved:        maps.Clone(m.reserved),
+		Exposure:        maps.Clone(m.exposure),
+		Posted:          slices.Clone(m.posted),
+		OrderIDs:        slices.Sorted(maps.Keys(m.orderIDs)),
+	}
+	for _, book := range m.books {
+		for _, order := range book.orders {
+			s.Orders = append(s.Orders, *order)
+		}
+	}
+	sort.Slice(s.Orders, func(i, j int) bool { return s.Orders[i].Seq < s.Orders[j].Seq })
+	if m.settlement != nil {
+		settlement := *m.settlement
+		s.Settlement = &settlement
+	}
+	return s
+}
+
+// RestoreSnapshot loads s into an engine that has no markets or ledger
+// entries yet, typically on startup. Commands logged after the snapshot
+// can then be replayed on top of it.
+func RestoreSnapshot(s Snapshot) error {
+	if s.Version != SnapshotVersion {
+		return fmt.Errorf("%w: %d", ErrSnapshotVersion, s.Version)
+	}
+	restored := make([]*market, 0, len(s.Markets))
+	for _, ms := range s.Markets {
+		m, err := ms.restore()
+		if err != nil {
+			return fmt.Errorf("match %s: %w", ms.MatchID, err)
+		}
+		restored = append(restored, m)
+	}
+
+	cmdLog.admitting.Lock()
+	defer cmdLog.admitting.Unlock()
+	mu.Lock()
+	defer mu.Unlock()
+	ledger.Lock()
+	defer ledger.Unlock()
+	if len(markets) > 0 || len(ledger.entries) > 0 {
+		return ErrEngineNotEmpty
+	}
+
+	ledger.entries = slices.Clone(s.Ledger)
+	for _, entry := range ledger.entries {
+		for _, p := range entry.Postings {
+			ledger.balances[p.Account] += p.Amount
+		}
+	}
+	cmdLog.seq = s.CommandSeq
+	for _, m := range restored {
+		markets[m.id] = m
+		go m.run()
+	}
+	log.Printf("Restored %d markets from snapshot taken at %s", len(restored), s.TakenAt.Format(time.RFC3339))
+	return nil
+}
+
+// restore rebuilds the market. Orders are pushed in acceptance order, so
+// each price level queues them exactly as before.
+func (s MarketSnapshot) restore() (*market, error) {
+	m, err := newMarket(s.MatchID, s.Runners, s.Start)
+	if err != nil {
+		return nil, err
+	}
+	m.state = s.State
+	m.selfTradePolicy = s.SelfTradePolicy
+	m.surplusPolicy = s.SurplusPolicy
+	m.maxExposure = s.MaxExposure
+	m.trades = slices.Clone(s.Trades)
+	m.posted = slices.Clone(s.Posted)
+	for _, id := range s.OrderIDs {
+		m.orderIDs[id] = struct{}{}
+	}
+	maps.Copy(m.reserved, s.Reserved)
+	maps.Copy(m.exposure, s.Exposure)
+	if s.Settlement != nil {
+		settlement := *s.Settlement
+		m.settlement = &settlement
+	}
+
+	orders := slices.Clone(s.Orders)
+	sort.Slice(orders, func(i, j int) bool { return orders[i].Seq < orders[j].Seq })
+	for i := range orders {
+		book, ok := m.books[orders[i].TeamID]
+		if !ok {
+			return nil, fmt.Errorf("%w: order %s on %q", ErrUnknownRunner, orders[i].ID, orders[i].TeamID)
+		}
+		book.rest(&orders[i])
+	}
+	for _, trade := range m.trades {
+		for _, leg := range trade.Legs {
+			m.positionOf(leg.UserID).add(leg, m.runners)
+		}
+	}
+	return m, nil
+}
+
+// WriteSnapshot takes a snapshot and writes it to a new file in dir,
+// returning the file's path. The file only appears once it is complete,
+// and only once every command and trade it includes is on disk in the
+// logs: a snapshot ahead of the command log would have new commands
+// numbered past a gap, and one ahead of the trade log would hold trades
+// the log never signed.
+func WriteSnapshot(dir string) (string, error) {
+	s := TakeSnapshot()
+	data, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".snapshot-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	// Everything the snapshot includes was written before it was taken,
+	// so syncing now covers it.
+	if err := cmdLog.commit(); err != nil {
+		return "", err
+	}
+	if err := commitTrades(); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, snapshotName(s.TakenAt))
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+
+	files, err := snapshotFiles(dir)
+	if err != nil {
+		return path, err
+	}
+	for _, old := range files[:max(0, len(files)-snapshotsKept)] {
+		if err := os.Remove(old); err != nil {
+			log.Printf("Old snapshot %s not removed: %v", old, err)
+		}
+	}
+	return path, nil
+}
+
+// LoadLatestSnapshot restores the newest snapshot in dir and returns its
+// path, or "" when there is none.
+func LoadLatestSnapshot(dir string) (string, error) {
+	files, err := snapshotFiles(dir)
+	if err != nil || len(files) == 0 {
+		return "", err
+	}
+	path := files[len(files)-1]
+	if err := LoadSnapshot(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// LoadSnapshot restores the snapshot in the file at path.
+func LoadSnapshot(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := RestoreSnapshot(s); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// StartSnapshotter writes a snapshot to dir every interval in the
+// background.
+func StartSnapshotter(dir string, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if _, err := WriteSnapshot(dir); err != nil {
+				log.Printf("Snapshot failed: %v", err)
+			}
+		}
+	}()
+}
+
+// snapshotName names a snapshot so that names sort by the time taken.
+func snapshotName(takenAt time.Time) string {
+	return "snapshot-" + takenAt.UTC().Format("20060102T150405.000000000Z") + ".json"
+}
+
+// snapshotFiles lists the snapshots in dir, oldest first.
+func snapshotFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "snapshot-*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
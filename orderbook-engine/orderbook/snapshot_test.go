@@ -0,0 +1,213 @@
+package orderbook
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amithshubhan/Bet_Now/orderbook-engine/tradelog"
+)
+
+// TestSnapshotWhileTrading takes snapshots while orders stream into several
+// markets and new markets are registered. Every snapshot must complete and
+// be a consistent cut: each user's reserved balance in the ledger equals
+// what the markets hold for their resting orders.
+func TestSnapshotWhileTrading(t *testing.T) {
+	const marketCount, snapshots = 4, 20
+	users := []string{"snap-u1", "snap-u2", "snap-u3"}
+	for _, user := range users {
+		if _, err := Deposit(user, 1_000_000_00); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range marketCount {
+		matchID := fmt.Sprintf("snap-m%d", i)
+		if err := RegisterMarket(matchID, []string{"a", "b"}, time.Now().Add(time.Hour), MarketOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := TransitionMarket(matchID, MarketOpen, ""); err != nil {
+			t.Fatal(err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; ; n++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				order := Order{MatchID: matchID, TeamID: "a", UserID: users[n%len(users)], Side: "ask", Price: 250, Quantity: 100}
+				if n%2 == 1 {
+					order.Side, order.Price = "bid", 240
+				}
+				PlaceOrder(order)
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for n := range 100 {
+			select {
+			case <-stop:
+				return
+			case <-time.After(time.Millisecond):
+			}
+			RegisterMarket(fmt.Sprintf("snap-new%d", n), []string{"a", "b"}, time.Now().Add(time.Hour), MarketOptions{})
+		}
+	}()
+	for range snapshots {
+		taken := make(chan Snapshot, 1)
+		go func() { taken <- TakeSnapshot() }()
+		select {
+		case s := <-taken:
+			checkSnapshotCut(t, s, users)
+		case <-time.After(10 * time.Second):
+			t.Fatal("TakeSnapshot did not return while markets were trading")
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
+
+// checkSnapshotCut compares each user's reserved ledger balance with the
+// funds the snapshot's markets hold for that user's resting orders.
+func checkSnapshotCut(t *testing.T, s Snapshot, users []string) {
+	t.Helper()
+	balances := make(map[string]Money)
+	for _, entry := range s.Ledger {
+		if !entry.balanced() {
+			t.Fatalf("ledger entry %d is unbalanced", entry.Seq)
+		}
+		for _, p := range entry.Postings {
+			balances[p.Account] += p.Amount
+		}
+	}
+	held := make(map[string]Money)
+	for _, ms := range s.Markets {
+		for _, order := range ms.Orders {
+			held[order.UserID] += ms.Reserved[order.ID]
+		}
+	}
+	for _, user := range users {
+		if got, want := balances[reservedAccount(user)], held[user]; got != want {
+			t.Fatalf("%s: ledger reserves %s, resting orders hold %s", user, got, want)
+		}
+	}
+}
+
+// applyUnacknowledged places order the way PlaceOrder does, but returns
+// once it is applied, before its records are synced: the state a snapshot
+// finds a command in while its caller is still waiting on the sync.
+func applyUnacknowledged(t *testing.T, order Order) {
+	t.Helper()
+	m, err := lookupMarket(order.MatchID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := Command{Type: CommandPlaceOrder, MatchID: m.id, Order: &order}
+	done := make(chan struct{})
+	if err := cmdLog.admit(&cmd, nil, func() {
+		m.commands <- func() {
+			defer close(done)
+			m.cmd, m.traded = stamp{cmd.Seq, cmd.At}, 0
+			m.placeOrder(order)
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+}
+
+// TestWriteSnapshotSyncsLogs writes a snapshot mid-session, while a command
+// that traded is applied but not yet acknowledged, and checks that the
+// command and trade logs were on disk and signed before it appeared.
+// The full log must then replay from scratch, and from the snapshot, to
+// the state the session ended in.
+func TestWriteSnapshotSyncsLogs(t *testing.T) {
+	isolateEngine(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "commands.log")
+	if _, err := OpenCommandLog(path); err != nil {
+		t.Fatal(err)
+	}
+	defer CloseCommandLog()
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tradesPath := filepath.Join(dir, "trades.log")
+	if err := OpenTradeLog(tradesPath, key); err != nil {
+		t.Fatal(err)
+	}
+	defer CloseTradeLog()
+
+	matchID, user := openTestMarket(t, []string{"a", "b"}, "backer", "layer")
+	place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("layer"), Side: "ask", Price: 3_00, Quantity: 10_00})
+	applyUnacknowledged(t, Order{MatchID: matchID, TeamID: "a", UserID: user("backer"), Side: "bid", Price: 3_00, Quantity: 4_00})
+
+	snapshot, err := WriteSnapshot(filepath.Join(dir, "snapshots"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmdLog.syncing.Lock()
+	synced, written := cmdLog.synced, cmdLog.written
+	cmdLog.syncing.Unlock()
+	if synced != written {
+		t.Errorf("snapshot written with %d of %d command log records synced", synced, written)
+	}
+	head, err := tradelog.ReadHead(tradelog.HeadPath(tradesPath), pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(tradesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := tradelog.Verify(file, pub, head)
+	file.Close()
+	if err != nil || report.Records != 1 || report.Unsigned != 0 {
+		t.Errorf("trade log at the snapshot: %+v, %v", report, err)
+	}
+
+	place(t, Order{MatchID: matchID, TeamID: "a", UserID: user("backer"), Side: "bid", Price: 3_00, Quantity: 6_00})
+	if _, err := SettleMatch(matchID, "a"); err != nil {
+		t.Fatal(err)
+	}
+	want := engineState(t)
+	if err := CloseTradeLog(); err != nil {
+		t.Fatal(err)
+	}
+	if err := CloseCommandLog(); err != nil {
+		t.Fatal(err)
+	}
+
+	resetEngine()
+	if _, err := ReplayCommands(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := engineState(t); !bytes.Equal(got, want) {
+		t.Errorf("replay from scratch differs:\n got %s\nwant %s", got, want)
+	}
+
+	resetEngine()
+	if err := LoadSnapshot(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReplayCommands(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := engineState(t); !bytes.Equal(got, want) {
+		t.Errorf("replay from the snapshot differs:\n got %s\nwant %s", got, want)
+	}
+}
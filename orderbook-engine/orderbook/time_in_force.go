@@ -0,0 +1,229 @@
+package orderbook
+
+import (
+	"log"
+	"time"
+)
+
+// Time-in-force values for Order.TimeInForce.
+const (
+	TimeInForceGTC = "GTC" // good till cancelled: rest the remainder
+	TimeInForceIOC = "IOC" // immediate or cancel: discard the remainder
+	TimeInForceFOK = "FOK" // fill or kill: fill completely or not at all
+	TimeInForceGTD = "GTD" // good till date: rest until ExpiresAt
+)
+
+// now is the engine clock.
+var now = time.Now
+
+// rests reports whether the unfilled remainder of the order stays in the book.
+func (o *Order) rests() bool {
+	if o.Type == OrderTypeMarket {
+		return false
+	}
+	return o.TimeInForce == TimeInForceGTC || o.TimeInForce == TimeInForceGTD
+}
+
+func isExpired(o *Order, at time.Time) bool {
+	return o.TimeInForce == TimeInForceGTD && !at.Before(o.ExpiresAt)
+}
+
+// expireOrder removes an expired order from the book and reports it.
+func (m *market) expireOrder(book *OrderBook, order *Order) {
+	book.remove(order)
+	m.release(order)
+	reportExpired(*order)
+}
+
+func reportExpired(order Order) {
+	log.Printf("Order %s expired: %s units at %s unfilled", order.ID, order.Quantity, order.Price)
+	PublishOrderEvent(OrderEventExpired, order)
+}
+
+// --- Fill-or-Kill ---
+
+// fillableQuantity returns how much of the order could be filled right now
+// in its own book and across the other runners, without touching them.
+func (m *market) fillableQuantity(order Order, book *OrderBook) Money {
+	at := m.cmd.at
+	var total Money
+
+	if order.Side == "bid" {
+		for _, ask := range book.Asks.Orders() {
+			if isExpired(ask, at) {
+				continue
+			}
+			if !order.acceptsPrice(ask.Price) {
+				break
+			}
+			if ask.UserID == order.UserID {
+				if m.selfTradeStopsFill() {
+					return total
+				}
+				continue
+			}
+			total += ask.Quantity
+		}
+		total = m.crossFillable(order, total, at)
+	} else if order.Side == "ask" {
+		for _, bid := range book.Bids.Orders() {
+			if isExpired(bid, at) {
+				continue
+			}
+			if !order.acceptsPrice(bid.Price) {
+				break
+			}
+			if bid.UserID == order.UserID {
+				if m.selfTradeStopsFill() {
+					return total
+				}
+				continue
+			}
+			total += bid.Quantity
+		}
+		total = m.crossFillable(order, total, at)
+	}
+	return total
+}
+
+// crossFillable adds to total how much more of the order could cross with
+// the other runners.
+func (m *market) crossFillable(order Order, total Money, at time.Time) Money {
+	m.replayCross(order, order.Quantity-total, at, func(legs []crossLeg) bool {
+		total += legs[0].matched
+		return true
+	})
+	return total
+}
+
+// replayCross replays matchWithOtherRunners for up to quantity of the order
+// on copies of the other runners' books, passing each cross it would make
+// to fill until fill returns false. The books are not touched.
+func (m *market) replayCross(order Order, quantity Money, at time.Time, fill func(legs []crossLeg) bool) {
+	var queues [][]*Order
+	for _, runner := range m.otherRunners(order.TeamID) {
+		var live []*Order
+		for _, o := range m.books[runner].side(order.Side).Orders() {
+			if !isExpired(o, at) {
+				live = append(live, o)
+			}
+		}
+		queues = append(queues, live)
+	}
+	left := make(map[*Order]Money)
+
+	for quantity > 0 {
+		makers := make([]*Order, len(queues))
+		for i, queue := range queues {
+			if len(queue) == 0 {
+				return
+			}
+			makers[i] = queue[0]
+			if _, seen := left[queue[0]]; !seen {
+				left[queue[0]] = queue[0].Quantity
+			}
+		}
+		fair := fairCrossOdds(order.Side, makerOdds(makers))
+		if fair == 0 || !order.acceptsPrice(fair) {
+			break
+		}
+		if i := selfTradeMaker(order, makers); i >= 0 {
+			if m.selfTradeStopsFill() {
+				break
+			}
+			queues[i] = queues[i][1:]
+			continue
+		}
+
+		legs := newCrossLegs(&order, fair, quantity, makers)
+		for i, maker := range makers {
+			legs[i+1].cap = left[maker]
+		}
+		if !sizeCross(order.Side, legs, m.surplusPolicy) || !fill(legs) {
+			break
+		}
+		quantity -= legs[0].matched
+		for i, maker := range makers {
+			left[maker] -= legs[i+1].matched
+			if left[maker] <= 0 {
+				queues[i] = queues[i][1:]
+			}
+		}
+	}
+}
+
+// --- Expiry Sweeper ---
+
+// ExpireOrders removes every GTD order whose expiry has passed and returns
+// the orders that were expired. Markets that are too busy are skipped until
+// the next sweep.
+func ExpireOrders(at time.Time) []Order {
+	var expired []Order
+	for _, m := range allMarkets() {
+		due, err := execute(m, func() (bool, error) {
+			return m.hasExpired(at), nil
+		})
+		var orders []Order
+		if err == nil && due {
+			orders, err = m.expire(at)
+		}
+		if err != nil {
+			log.Printf("Skipping expiry for match %s: %v", m.id, err)
+			continue
+		}
+		expired = append(expired, orders...)
+	}
+	return expired
+}
+
+// expire logs an expiry command for at and expires whatever has expired by
+// then. A sweep that finds nothing to expire changes nothing, so the sweep
+// checks first and only sweeps that find something are logged. The orders
+// are expired as of the command's logged time, never the clock, so a
+// replay expires exactly the orders the original run did, even if a
+// command that came between the check and the expiry changed the book.
+func (m *market) expire(at time.Time) ([]Order, error) {
+	return marketCommand(m, Command{Type: CommandExpire, MatchID: m.id, At: at}, func() ([]Order, error) {
+		return m.expireOrders(m.cmd.at), nil
+	})
+}
+
+// hasExpired reports whether any resting order has expired as of at.
+func (m *market) hasExpired(at time.Time) bool {
+	for _, runner := range m.runners {
+		book := m.books[runner]
+		for _, order := range append(book.Bids.Orders(), book.Asks.Orders()...) {
+			if isExpired(order, at) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// expireOrders removes every order that has expired as of at, runner by
+// runner and in priority order within each side.
+func (m *market) expireOrders(at time.Time) []Order {
+	var expired []Order
+	for _, runner := range m.runners {
+		book := m.books[runner]
+		for _, order := range append(book.Bids.Orders(), book.Asks.Orders()...) {
+			if isExpired(order, at) {
+				m.expireOrder(book, order)
+				expired = append(expired, *order)
+			}
+		}
+	}
+	return expired
+}
+
+// StartExpirySweeper expires GTD orders every interval in the background.
+func StartExpirySweeper(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			ExpireOrders(now())
+		}
+	}()
+}
@@ -0,0 +1,101 @@
+package orderbook
+
+import (
+	"fmt"
+	"time"
+)
+
+// Trade is one execution between orders, recorded on the market so the
+// match can be settled from it.
+type Trade struct {
+	ID        string     `json:"id"`
+	Command   uint64     `json:"command"` // Seq of the command that made it
+	MatchID   string     `json:"match_id"`
+	MatchType string     `json:"match_type"` // MatchSameTeam or MatchCrossTeam
+	Legs      []TradeLeg `json:"legs"`
+	Timestamp time.Time  `json:"timestamp"`
+}
+
+// TradeLeg is one order's side of a trade: what it pays if its bet loses
+// and what it is paid if the bet wins.
+type TradeLeg struct {
+	OrderID string `json:"order_id"`
+	UserID  string `json:"user_id"`
+	TeamID  string `json:"team_id"`
+	Side    string `json:"side"` // "bid" backs TeamID, "ask" lays it
+	Odds    Odds   `json:"odds"`
+	Risk    Money  `json:"risk"`
+	Win     Money  `json:"win"`
+}
+
+// wins reports whether the leg's bet wins when winner wins the match.
+func (l TradeLeg) wins(winner string) bool {
+	return (l.TeamID == winner) == (l.Side == "bid")
+}
+
+// pnl is what the leg makes or loses when winner wins the match.
+func (l TradeLeg) pnl(winner string) Money {
+	if l.wins(winner) {
+		return l.Win
+	}
+	return -l.Risk
+}
+
+// recordTrade moves each leg's risk from the funds reserved for its order
+// into the match's escrow, then keeps the trade for settlement and writes it
+// to the trade log. The fill is reserved and posted first: if either falls
+// short, nothing is recorded and the caller must leave the books as they
+// were. A trade is named after its command and its place among the
+// command's trades, so a replay names it the same.
+func (m *market) recordTrade(matchType string, legs []TradeLeg) (Trade, error) {
+	trade := Trade{
+		ID:        tradeID(m.cmd.seq, m.traded+1),
+		Command:   m.cmd.seq,
+		MatchID:   m.id,
+		MatchType: matchType,
+		Legs:      legs,
+		Timestamp: m.cmd.at,
+	}
+	if err := m.consume(legs); err != nil {
+		return Trade{}, err
+	}
+	if _, err := post(m.cmd, fillEntry(trade)); err != nil {
+		m.unconsume(legs)
+		return Trade{}, fmt.Errorf("trade %s not posted: %w", trade.ID, err)
+	}
+	m.traded++
+	m.trades = append(m.trades, trade)
+	logTrade(trade)
+	for _, leg := range legs {
+		m.positionOf(leg.UserID).add(leg, m.runners)
+	}
+	return trade, nil
+}
+
+// tradeID names the nth trade of command seq.
+func tradeID(seq uint64, n int) string {
+	return fmt.Sprintf("%d-%d", seq, n)
+}
+
+// parseTradeID splits a trade ID into its command and place.
+func parseTradeID(id string) (seq uint64, n int, err error) {
+	_, err = fmt.Sscanf(id, "%d-%d", &seq, &n)
+	return seq, n, err
+}
+
+// fillEntry debits every leg's risk from reserved funds and credits the
+// total to escrow.
+func fillEntry(trade Trade) JournalEntry {
+	var postings []Posting
+	var total Money
+	for _, leg := range trade.Legs {
+		postings = addPosting(postings, reservedAccount(leg.UserID), -leg.Risk)
+		total += leg.Risk
+	}
+	return JournalEntry{
+		Kind:     EntryFill,
+		MatchID:  trade.MatchID,
+		Ref:      trade.ID,
+		Postings: addPosting(postings, escrowAccount(trade.MatchID), total),
+	}
+}
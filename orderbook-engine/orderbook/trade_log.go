@@ -0,0 +1,223 @@
+package orderbook
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/amithshubhan/Bet_Now/orderbook-engine/tradelog"
+)
+
+// tradeLog is the tamper-evident record of every trade, or nil when it is
+// not kept.
+//
+// Trades are group committed, like the command log's records. A market
+// only checks each trade it makes and queues it, under a short lock. The
+// queue is written to the log and signed with one checkpoint before any
+// command that made one of its trades is acknowledged; commands waiting
+// together share that checkpoint. So markets trading side by side pay for
+// one checkpoint per batch, never one per trade, and no acknowledged trade
+// is ever left unsigned.
+var tradeLog struct {
+	sync.Mutex
+	log       *tradelog.Log
+	last      map[string]loggedTrade     // matchID → last trade logged for it
+	pending   map[string]json.RawMessage // trade ID → logged trade the engine has not applied yet
+	unwritten []json.RawMessage          // trades checked but not yet written
+	logged    uint64                     // trades ever queued
+
+	committing sync.Mutex
+	signed     uint64 // trades written and signed; guarded by committing
+}
+
+// loggedTrade is where a trade stands among its market's trades.
+type loggedTrade struct {
+	id  string
+	seq uint64 // its command
+	n   int    // its place among the command's trades
+}
+
+// OpenTradeLog starts writing every trade to the hash-chained log at path,
+// with checkpoints signed by key. Open it after restoring a snapshot and
+// before replaying the command log: the trades replayed commands make must
+// then be exactly the ones the log already holds.
+func OpenTradeLog(path string, key ed25519.PrivateKey) error {
+	l, err := tradelog.Open(path, key)
+	if err != nil {
+		return err
+	}
+	pub := key.Public().(ed25519.PublicKey)
+	head, err := tradelog.ReadHead(tradelog.HeadPath(path), pub)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		l.Close()
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		l.Close()
+		return err
+	}
+	defer file.Close()
+
+	applied := cmdLog.admitted()
+	last := make(map[string]loggedTrade)
+	pending := make(map[string]json.RawMessage)
+	if _, err := tradelog.Scan(file, pub, head, func(entry tradelog.Entry) error {
+		var trade Trade
+		if err := json.Unmarshal(entry.Data, &trade); err != nil {
+			return err
+		}
+		logged, err := loggedAs(trade)
+		if err != nil {
+			return err
+		}
+		last[trade.MatchID] = logged
+		if trade.Command > applied {
+			pending[trade.ID] = entry.Data
+		}
+		return nil
+	}); err != nil {
+		l.Close()
+		return err
+	}
+
+	tradeLog.Lock()
+	defer tradeLog.Unlock()
+	tradeLog.log, tradeLog.last, tradeLog.pending, tradeLog.unwritten = l, last, pending, nil
+	return nil
+}
+
+// CloseTradeLog writes and signs every trade queued so far and closes the
+// trade log.
+func CloseTradeLog() error {
+	tradeLog.committing.Lock()
+	defer tradeLog.committing.Unlock()
+	err := signTrades()
+	tradeLog.Lock()
+	l := tradeLog.log
+	tradeLog.log = nil
+	tradeLog.Unlock()
+	if l == nil {
+		return err
+	}
+	if closeErr := l.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// logTrade queues trade for the trade log. A trade the log holds already,
+// because a replay made it again, must be exactly the one logged; any
+// other trade must follow the last one logged for its match. If neither
+// holds the engine halts rather than let the log and the trades part ways.
+func logTrade(trade Trade) {
+	tradeLog.Lock()
+	defer tradeLog.Unlock()
+	if tradeLog.log == nil {
+		return
+	}
+	if err := queueTrade(trade); err != nil {
+		cmdLog.halt(fmt.Errorf("trade %s: %w", trade.ID, err))
+	}
+}
+
+// queueTrade checks trade and queues it; the caller holds the lock.
+func queueTrade(trade Trade) error {
+	data, err := json.Marshal(trade)
+	if err != nil {
+		return err
+	}
+	if logged, ok := tradeLog.pending[trade.ID]; ok {
+		delete(tradeLog.pending, trade.ID)
+		if !bytes.Equal(logged, data) {
+			return fmt.Errorf("%w: the trade log holds %s", ErrReplayDiverged, logged)
+		}
+		return nil
+	}
+
+	next, err := loggedAs(trade)
+	if err != nil {
+		return err
+	}
+	if last, ok := tradeLog.last[trade.MatchID]; ok &&
+		!(next.seq == last.seq && next.n == last.n+1 || next.seq > last.seq && next.n == 1) {
+		return fmt.Errorf("%w: follows %s", ErrTradeOutOfSequence, last.id)
+	}
+	tradeLog.unwritten = append(tradeLog.unwritten, data)
+	tradeLog.logged++
+	tradeLog.last[trade.MatchID] = next
+	return nil
+}
+
+func loggedAs(trade Trade) (loggedTrade, error) {
+	seq, n, err := parseTradeID(trade.ID)
+	if err != nil || seq != trade.Command || n < 1 {
+		return loggedTrade{}, fmt.Errorf("%w: trade ID %q", ErrTradeOutOfSequence, trade.ID)
+	}
+	return loggedTrade{id: trade.ID, seq: seq, n: n}, nil
+}
+
+// commitTrades returns once every trade queued so far is written and
+// signed, so none can be cut from the log unnoticed. Callers that arrive
+// while a batch is being signed share the next one. A failure halts the
+// engine.
+func commitTrades() error {
+	tradeLog.Lock()
+	target := tradeLog.logged
+	tradeLog.Unlock()
+
+	tradeLog.committing.Lock()
+	defer tradeLog.committing.Unlock()
+	if tradeLog.signed < target {
+		if err := signTrades(); err != nil {
+			cmdLog.halt(fmt.Errorf("trade log: %w", err))
+		}
+	}
+	return cmdLog.haltErr()
+}
+
+// signTrades writes the queued trades and signs them with one checkpoint;
+// the caller holds committing.
+func signTrades() error {
+	tradeLog.Lock()
+	l, batch, logged := tradeLog.log, tradeLog.unwritten, tradeLog.logged
+	tradeLog.unwritten = nil
+	tradeLog.Unlock()
+	if l == nil || len(batch) == 0 {
+		tradeLog.signed = logged
+		return nil
+	}
+	// The log must never hold a trade a replay cannot make again, so the
+	// commands that made them go to disk first.
+	if err := cmdLog.commit(); err != nil {
+		return err
+	}
+	for _, data := range batch {
+		if _, err := l.Append(data); err != nil {
+			return err
+		}
+	}
+	if _, err := l.Checkpoint(); err != nil {
+		return err
+	}
+	tradeLog.signed = logged
+	return nil
+}
+
+// checkReplayedTrades fails if a replay left trades in the log that it did
+// not make again.
+func checkReplayedTrades() error {
+	tradeLog.Lock()
+	defer tradeLog.Unlock()
+	if len(tradeLog.pending) == 0 {
+		return nil
+	}
+	ids := slices.Sorted(maps.Keys(tradeLog.pending))
+	return fmt.Errorf("%w: %d logged trades were not made again, such as %s", ErrReplayDiverged, len(ids), ids[0])
+}
@@ -0,0 +1,192 @@
+package orderbook
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amithshubhan/Bet_Now/orderbook-engine/tradelog"
+)
+
+// TestLogTradeHaltsOutOfSequence checks that a trade which does not follow
+// the last one logged for its match halts the engine instead of being
+// dropped or written.
+func TestLogTradeHaltsOutOfSequence(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := OpenTradeLog(filepath.Join(t.TempDir(), "trades.log"), key); err != nil {
+		t.Fatal(err)
+	}
+	defer CloseTradeLog()
+	t.Cleanup(func() { cmdLog.halted.Store(nil) })
+
+	trade := func(seq uint64, n int) Trade {
+		return Trade{ID: tradeID(seq, n), Command: seq, MatchID: "m"}
+	}
+	for _, tr := range []Trade{trade(1, 1), trade(1, 2), trade(4, 1)} {
+		logTrade(tr)
+		if err := cmdLog.haltErr(); err != nil {
+			t.Fatalf("trade %s: %v", tr.ID, err)
+		}
+	}
+	for _, tr := range []Trade{trade(4, 3), trade(4, 1), trade(3, 1)} {
+		logTrade(tr)
+		if err := cmdLog.haltErr(); !errors.Is(err, ErrTradeOutOfSequence) {
+			t.Errorf("trade %s: got %v, want %v", tr.ID, err, ErrTradeOutOfSequence)
+		}
+		cmdLog.halted.Store(nil)
+	}
+}
+
+// TestTradesSignedBeforeAcknowledged trades in several markets side by
+// side and checks that, once every order has returned, each trade is in
+// the log and signed, without the log being closed.
+func TestTradesSignedBeforeAcknowledged(t *testing.T) {
+	const marketCount, tradesEach = 4, 10
+	isolateEngine(t)
+	dir := t.TempDir()
+	if _, err := OpenCommandLog(filepath.Join(dir, "commands.log")); err != nil {
+		t.Fatal(err)
+	}
+	defer CloseCommandLog()
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "trades.log")
+	if err := OpenTradeLog(path, key); err != nil {
+		t.Fatal(err)
+	}
+	defer CloseTradeLog()
+
+	for _, user := range []string{"backer", "layer"} {
+		if _, err := Deposit(user, 10_000_00); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var wg sync.WaitGroup
+	for i := range marketCount {
+		matchID := fmt.Sprintf("signed-%d", i)
+		if err := RegisterMarket(matchID, []string{"a", "b"}, now().Add(time.Hour), MarketOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := TransitionMarket(matchID, MarketOpen, ""); err != nil {
+			t.Fatal(err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range tradesEach {
+				if _, err := PlaceOrder(Order{MatchID: matchID, TeamID: "a", UserID: "layer", Side: "ask", Price: 2_00, Quantity: 1_00}); err != nil {
+					t.Errorf("lay: %v", err)
+					return
+				}
+				if report, err := PlaceOrder(Order{MatchID: matchID, TeamID: "a", UserID: "backer", Side: "bid", Price: 2_00, Quantity: 1_00}); err != nil || report.Status != StatusFilled {
+					t.Errorf("back: %+v, %v", report, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	head, err := tradelog.ReadHead(tradelog.HeadPath(path), pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	report, err := tradelog.Verify(file, pub, head)
+	if err != nil || report.Records != marketCount*tradesEach || report.Unsigned != 0 {
+		t.Errorf("trade log: %+v, %v", report, err)
+	}
+}
+
+// BenchmarkLoggedTrades trades in several markets side by side with the
+// command and trade logs on disk, each trade acknowledged only once it is
+// synced and signed. Commands waiting together share the sync and the
+// checkpoint, which checkpoints/trade shows.
+func BenchmarkLoggedTrades(b *testing.B) {
+	const marketCount = 8
+	isolateEngine(b)
+	dir := b.TempDir()
+	if _, err := OpenCommandLog(filepath.Join(dir, "commands.log")); err != nil {
+		b.Fatal(err)
+	}
+	defer CloseCommandLog()
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		b.Fatal(err)
+	}
+	path := filepath.Join(dir, "trades.log")
+	if err := OpenTradeLog(path, key); err != nil {
+		b.Fatal(err)
+	}
+	defer CloseTradeLog()
+
+	for i := range marketCount {
+		matchID := fmt.Sprintf("bench-%d", i)
+		if err := RegisterMarket(matchID, []string{"a", "b"}, now().Add(time.Hour), MarketOptions{}); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := TransitionMarket(matchID, MarketOpen, ""); err != nil {
+			b.Fatal(err)
+		}
+	}
+	for _, user := range []string{"bench-back", "bench-lay"} {
+		if _, err := Deposit(user, 1_000_000_000_00); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	var next atomic.Int64
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		matchID := fmt.Sprintf("bench-%d", next.Add(1)%marketCount)
+		for pb.Next() {
+			lay := Order{MatchID: matchID, TeamID: "a", UserID: "bench-lay", Side: "ask", Price: 2_00, Quantity: 1_00}
+			back := Order{MatchID: matchID, TeamID: "a", UserID: "bench-back", Side: "bid", Price: 2_00, Quantity: 1_00}
+			if _, err := PlaceOrder(lay); err != nil {
+				b.Error(err)
+				return
+			}
+			if report, err := PlaceOrder(back); err != nil || report.Status != StatusFilled {
+				b.Errorf("back: %+v, %v", report, err)
+				return
+			}
+		}
+	})
+	b.StopTimer()
+
+	if err := CloseTradeLog(); err != nil {
+		b.Fatal(err)
+	}
+	head, err := tradelog.ReadHead(tradelog.HeadPath(path), pub)
+	if err != nil {
+		b.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer file.Close()
+	report, err := tradelog.Verify(file, pub, head)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if report.Records > 0 {
+		b.ReportMetric(float64(report.Checkpoints)/float64(report.Records), "checkpoints/trade")
+	}
+}
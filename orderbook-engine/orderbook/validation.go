@@ -0,0 +1,119 @@
+package orderbook
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+// RejectCode says why an order was not accepted.
+type RejectCode string
+
+const (
+	RejectUnknownMatch       RejectCode = "UNKNOWN_MATCH"
+	RejectMarketNotOpen      RejectCode = "MARKET_NOT_OPEN"
+	RejectUnknownTeam        RejectCode = "UNKNOWN_TEAM"
+	RejectMissingUser        RejectCode = "MISSING_USER"
+	RejectInvalidSide        RejectCode = "INVALID_SIDE"
+	RejectInvalidOrderType   RejectCode = "INVALID_ORDER_TYPE"
+	RejectInvalidTimeInForce RejectCode = "INVALID_TIME_IN_FORCE"
+	RejectInvalidPrice       RejectCode = "INVALID_PRICE"
+	RejectOffLadder          RejectCode = "OFF_LADDER"
+	RejectMissingWorstPrice  RejectCode = "MISSING_WORST_PRICE"
+	RejectInvalidQuantity    RejectCode = "INVALID_QUANTITY"
+	RejectInvalidExpiry      RejectCode = "INVALID_EXPIRY"
+	RejectDuplicateOrderID   RejectCode = "DUPLICATE_ORDER_ID"
+	RejectReservedOrderID    RejectCode = "RESERVED_ORDER_ID"
+	RejectInsufficientFunds  RejectCode = "INSUFFICIENT_FUNDS"
+	RejectExposureLimit      RejectCode = "EXPOSURE_LIMIT"
+)
+
+// rejectError is the error for an order the market rejected inside another
+// call, such as a cash-out: the error the same check returns elsewhere if
+// there is one, or ErrOrderRejected.
+func rejectError(report ExecutionReport) error {
+	err := ErrOrderRejected
+	switch report.RejectCode {
+	case RejectMarketNotOpen:
+		err = ErrMarketNotOpen
+	case RejectInsufficientFunds:
+		err = ErrInsufficientFunds
+	case RejectExposureLimit:
+		err = ErrExposureLimit
+	}
+	return fmt.Errorf("%w: %s: %s", err, report.RejectCode, report.Reason)
+}
+
+// minOdds is the lowest price that pays out more than the stake.
+const minOdds Odds = 1_00
+
+// validateOrder checks an order against the market before it is matched.
+// Limit prices and market-order bounds may be snapped onto the ladder.
+func (m *market) validateOrder(order *Order) (RejectCode, string) {
+	if !m.state.acceptsOrders() {
+		return RejectMarketNotOpen, "market is " + string(m.state)
+	}
+	if order.UserID == "" {
+		return RejectMissingUser, "user_id is required"
+	}
+	if order.Side != "bid" && order.Side != "ask" {
+		return RejectInvalidSide, `side must be "bid" or "ask"`
+	}
+	if _, ok := m.books[order.TeamID]; !ok {
+		return RejectUnknownTeam, "team is not in this match"
+	}
+	if order.Type != OrderTypeLimit && order.Type != OrderTypeMarket {
+		return RejectInvalidOrderType, `type must be "limit" or "market"`
+	}
+	switch order.TimeInForce {
+	case TimeInForceGTC, TimeInForceIOC, TimeInForceFOK, TimeInForceGTD:
+	default:
+		return RejectInvalidTimeInForce, "time_in_force must be GTC, IOC, FOK or GTD"
+	}
+	if order.Quantity <= 0 {
+		return RejectInvalidQuantity, "quantity must be positive"
+	}
+	// Without a bound a lay's liability could reach MaxOdds, so it would
+	// need almost a thousand times its stake reserved.
+	if order.Type == OrderTypeMarket && order.Side == "ask" && order.WorstPrice == 0 {
+		return RejectMissingWorstPrice, "market lays need a worst_price to bound their liability"
+	}
+
+	// Limit prices and market-order bounds must be real odds on the ladder.
+	if order.Type == OrderTypeLimit || order.WorstPrice != 0 {
+		limit := &order.Price
+		if order.Type == OrderTypeMarket {
+			limit = &order.WorstPrice
+		}
+		if *limit <= minOdds {
+			return RejectInvalidPrice, "odds must be greater than 1.00"
+		}
+		price, err := alignToLadder(*limit, order.Side)
+		if errors.Is(err, ErrOffLadder) {
+			return RejectOffLadder, err.Error()
+		}
+		*limit = price
+	}
+
+	if order.TimeInForce == TimeInForceGTD && order.ExpiresAt.IsZero() {
+		return RejectInvalidExpiry, "GTD order has no expiry and the match has no start time"
+	}
+	// IDs are never reused, even once an order has left the book, so a
+	// report or trade always refers to one order.
+	if _, used := m.orderIDs[order.ID]; used {
+		return RejectDuplicateOrderID, "an order with this id has already been placed in this match"
+	}
+	return "", ""
+}
+
+// rejectOrder reports an order that failed validation.
+func rejectOrder(order Order, code RejectCode, reason string) ExecutionReport {
+	log.Printf("Rejected order %s [%s]: %s", order.ID, code, reason)
+	PublishOrderRejection(order, code, reason)
+	return ExecutionReport{
+		OrderID:    order.ID,
+		Status:     StatusRejected,
+		RejectCode: code,
+		Reason:     reason,
+	}
+}
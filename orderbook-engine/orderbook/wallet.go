@@ -0,0 +1,133 @@
+package orderbook
+
+import (
+	"fmt"
+	"log"
+)
+
+// Wallet is a user's money: what they can bet or withdraw, and what is set
+// aside for their resting orders. Money at risk in matched trades is held
+// in the match's escrow until settlement.
+type Wallet struct {
+	UserID    string `json:"user_id"`
+	Available Money  `json:"available"`
+	Reserved  Money  `json:"reserved"`
+}
+
+// GetWallet returns a user's balances.
+func GetWallet(userID string) Wallet {
+	ledger.Lock()
+	defer ledger.Unlock()
+	return ledger.wallet(userID)
+}
+
+// Deposit credits amount to a user's available balance.
+func Deposit(userID string, amount Money) (Wallet, error) {
+	if err := checkTransfer(userID, amount); err != nil {
+		return Wallet{}, err
+	}
+	return engineCommand(Command{Type: CommandDeposit, UserID: userID, Amount: amount}, func(st stamp) (Wallet, error) {
+		return deposit(st, userID, amount)
+	})
+}
+
+func deposit(st stamp, userID string, amount Money) (Wallet, error) {
+	ledger.Lock()
+	defer ledger.Unlock()
+	if _, err := ledger.append(st, []JournalEntry{{
+		Kind: EntryDeposit,
+		Postings: []Posting{
+			{Account: FundingAccount, Amount: -amount},
+			{Account: availableAccount(userID), Amount: amount},
+		},
+	}}); err != nil {
+		return Wallet{}, err
+	}
+	log.Printf("Deposit: %s +₹%s", userID, amount)
+	return ledger.wallet(userID), nil
+}
+
+// Withdraw debits amount from a user's available balance.
+func Withdraw(userID string, amount Money) (Wallet, error) {
+	if err := checkTransfer(userID, amount); err != nil {
+		return Wallet{}, err
+	}
+	return engineCommand(Command{Type: CommandWithdraw, UserID: userID, Amount: amount}, func(st stamp) (Wallet, error) {
+		return withdraw(st, userID, amount)
+	})
+}
+
+func withdraw(st stamp, userID string, amount Money) (Wallet, error) {
+	ledger.Lock()
+	defer ledger.Unlock()
+	if available := ledger.balances[availableAccount(userID)]; !cmdLog.decideFunds(st.seq, available, amount) {
+		return Wallet{}, fmt.Errorf("%w: %s available", ErrInsufficientFunds, available)
+	}
+	if _, err := ledger.append(st, []JournalEntry{{
+		Kind: EntryWithdrawal,
+		Postings: []Posting{
+			{Account: availableAccount(userID), Amount: -amount},
+			{Account: FundingAccount, Amount: amount},
+		},
+	}}); err != nil {
+		return Wallet{}, err
+	}
+	log.Printf("Withdrawal: %s -₹%s", userID, amount)
+	return ledger.wallet(userID), nil
+}
+
+// reserveFunds moves amount of a user's available balance to reserved for
+// an order. Other markets draw on the same balance at the same time, so
+// whether it suffices is logged as a funds decision for the command st.
+func reserveFunds(st stamp, userID, matchID, orderID string, amount Money) error {
+	ledger.Lock()
+	defer ledger.Unlock()
+	if available := ledger.balances[availableAccount(userID)]; !cmdLog.decideFunds(st.seq, available, amount) {
+		return fmt.Errorf("%w: %s available, order needs %s", ErrInsufficientFunds, available, amount)
+	}
+	_, err := ledger.append(st, []JournalEntry{{
+		Kind:    EntryReserve,
+		MatchID: matchID,
+		Ref:     orderID,
+		Postings: []Posting{
+			{Account: availableAccount(userID), Amount: -amount},
+			{Account: reservedAccount(userID), Amount: amount},
+		},
+	}})
+	return err
+}
+
+// releaseFunds returns amount reserved for an order to the user's available
+// balance.
+func releaseFunds(st stamp, userID, matchID, orderID string, amount Money) {
+	if _, err := post(st, JournalEntry{
+		Kind:    EntryRelease,
+		MatchID: matchID,
+		Ref:     orderID,
+		Postings: []Posting{
+			{Account: reservedAccount(userID), Amount: -amount},
+			{Account: availableAccount(userID), Amount: amount},
+		},
+	}); err != nil {
+		log.Printf("Release for order %s not posted: %v", orderID, err)
+	}
+}
+
+func checkTransfer(userID string, amount Money) error {
+	if userID == "" {
+		return ErrMissingUser
+	}
+	if amount <= 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidAmount, amount)
+	}
+	return nil
+}
+
+// wallet reads a user's balances; the caller holds the lock.
+func (j *journal) wallet(userID string) Wallet {
+	return Wallet{
+		UserID:    userID,
+		Available: j.balances[availableAccount(userID)],
+		Reserved:  j.balances[reservedAccount(userID)],
+	}
+}
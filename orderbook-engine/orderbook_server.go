@@ -2,9 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/amithshubhan/Bet_Now/orderbook-engine/orderbook"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/amithshubhan/Bet_Now/orderbookpb"
 )
@@ -14,9 +19,382 @@ type orderbookServer struct {
 }
 
 func (s *orderbookServer) RegisterMatch(ctx context.Context, req *orderbookpb.MatchRequest) (*orderbookpb.RegisterMatchResponse, error) {
-	log.Printf("Registering match: %s (%s vs %s)", req.MatchId, req.TeamA, req.TeamB)
-	orderbook.RegisterMatch(req.MatchId, req.TeamA, req.TeamB)
+	runners := req.Runners
+	if len(runners) == 0 {
+		runners = []string{req.TeamA, req.TeamB}
+	}
+	log.Printf("Registering match: %s (%s)", req.MatchId, strings.Join(runners, " vs "))
+	var startTime time.Time
+	if req.StartTime > 0 {
+		startTime = time.Unix(req.StartTime, 0)
+	}
+	opts := orderbook.MarketOptions{
+		SelfTradePolicy: orderbook.SelfTradePolicy(req.SelfTradePolicy),
+		SurplusPolicy:   orderbook.SurplusPolicy(req.SurplusPolicy),
+	}
+	if req.MaxExposure != "" {
+		limit, err := orderbook.ParseMoney(req.MaxExposure)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "max_exposure: %v", err)
+		}
+		opts.MaxExposure = limit
+	}
+	if err := orderbook.RegisterMarket(req.MatchId, runners, startTime, opts); err != nil {
+		return nil, toStatusError(err)
+	}
 	return &orderbookpb.RegisterMatchResponse{
 		Status: "Match registered successfully",
 	}, nil
 }
+
+func (s *orderbookServer) TransitionMarket(ctx context.Context, req *orderbookpb.TransitionMarketRequest) (*orderbookpb.MarketStateResponse, error) {
+	state := orderbook.MarketState(req.State)
+	from, err := orderbook.TransitionMarket(req.MatchId, state, req.Reason)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &orderbookpb.MarketStateResponse{
+		MatchId:       req.MatchId,
+		State:         string(state),
+		PreviousState: string(from),
+	}, nil
+}
+
+func (s *orderbookServer) GetMarketState(ctx context.Context, req *orderbookpb.MarketStateRequest) (*orderbookpb.MarketStateResponse, error) {
+	state, err := orderbook.GetMarketState(req.MatchId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &orderbookpb.MarketStateResponse{
+		MatchId: req.MatchId,
+		State:   string(state),
+	}, nil
+}
+
+func (s *orderbookServer) SettleMatch(ctx context.Context, req *orderbookpb.SettleMatchRequest) (*orderbookpb.SettlementReport, error) {
+	report, err := orderbook.SettleMatch(req.MatchId, req.WinningTeamId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toSettlementProto(report), nil
+}
+
+func (s *orderbookServer) ResettleMatch(ctx context.Context, req *orderbookpb.SettleMatchRequest) (*orderbookpb.SettlementReport, error) {
+	report, err := orderbook.ResettleMatch(req.MatchId, req.WinningTeamId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toSettlementProto(report), nil
+}
+
+func (s *orderbookServer) VoidMatch(ctx context.Context, req *orderbookpb.VoidMatchRequest) (*orderbookpb.SettlementReport, error) {
+	report, err := orderbook.VoidMatch(req.MatchId, req.Reason)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toSettlementProto(report), nil
+}
+
+func toSettlementProto(report orderbook.SettlementReport) *orderbookpb.SettlementReport {
+	users := make([]*orderbookpb.UserSettlement, 0, len(report.Users))
+	for _, user := range report.Users {
+		users = append(users, &orderbookpb.UserSettlement{
+			UserId: user.UserID,
+			Staked: user.Staked.String(),
+			Won:    user.Won.String(),
+			Lost:   user.Lost.String(),
+			Net:    user.Net.String(),
+		})
+	}
+	return &orderbookpb.SettlementReport{
+		MatchId:       report.MatchID,
+		WinningTeamId: report.WinningTeamID,
+		Users:         users,
+		HouseNet:      report.HouseNet.String(),
+		SettledAt:     report.SettledAt.Unix(),
+		Voided:        report.Voided,
+	}
+}
+
+func (s *orderbookServer) Deposit(ctx context.Context, req *orderbookpb.WalletTransferRequest) (*orderbookpb.WalletResponse, error) {
+	amount, err := orderbook.ParseMoney(req.Amount)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "amount: %v", err)
+	}
+	wallet, err := orderbook.Deposit(req.UserId, amount)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toWalletProto(wallet), nil
+}
+
+func (s *orderbookServer) Withdraw(ctx context.Context, req *orderbookpb.WalletTransferRequest) (*orderbookpb.WalletResponse, error) {
+	amount, err := orderbook.ParseMoney(req.Amount)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "amount: %v", err)
+	}
+	wallet, err := orderbook.Withdraw(req.UserId, amount)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toWalletProto(wallet), nil
+}
+
+func (s *orderbookServer) GetWallet(ctx context.Context, req *orderbookpb.WalletRequest) (*orderbookpb.WalletResponse, error) {
+	if req.UserId == "" {
+		return nil, toStatusError(orderbook.ErrMissingUser)
+	}
+	return toWalletProto(orderbook.GetWallet(req.UserId)), nil
+}
+
+func (s *orderbookServer) GetPosition(ctx context.Context, req *orderbookpb.PositionRequest) (*orderbookpb.PositionResponse, error) {
+	position, err := orderbook.GetPosition(req.MatchId, req.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toPositionProto(position), nil
+}
+
+func toPositionProto(position orderbook.Position) *orderbookpb.PositionResponse {
+	runners := make([]*orderbookpb.RunnerPosition, 0, len(position.Runners))
+	for _, runner := range position.Runners {
+		runners = append(runners, &orderbookpb.RunnerPosition{
+			TeamId: runner.TeamID,
+			Backed: runner.Backed.String(),
+			Laid:   runner.Laid.String(),
+			IfWins: runner.IfWins.String(),
+		})
+	}
+	return &orderbookpb.PositionResponse{
+		MatchId: position.MatchID,
+		UserId:  position.UserID,
+		Staked:  position.Staked.String(),
+		Runners: runners,
+	}
+}
+
+func (s *orderbookServer) GetMarketDepth(ctx context.Context, req *orderbookpb.MarketDepthRequest) (*orderbookpb.MarketDepth, error) {
+	depth, err := orderbook.GetMarketDepth(req.MatchId, req.TeamId, int(req.Levels))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &orderbookpb.MarketDepth{
+		MatchId: depth.MatchID,
+		TeamId:  depth.TeamID,
+		Bids:    toDepthProto(depth.Bids),
+		Asks:    toDepthProto(depth.Asks),
+	}, nil
+}
+
+func toDepthProto(levels []orderbook.DepthLevel) []*orderbookpb.DepthLevel {
+	out := make([]*orderbookpb.DepthLevel, 0, len(levels))
+	for _, level := range levels {
+		out = append(out, &orderbookpb.DepthLevel{
+			Price:    level.Price.String(),
+			Quantity: level.Quantity.String(),
+			Orders:   int32(level.Orders),
+			Virtual:  level.Virtual.String(),
+		})
+	}
+	return out
+}
+
+func (s *orderbookServer) GetCashOutQuote(ctx context.Context, req *orderbookpb.CashOutRequest) (*orderbookpb.CashOutQuote, error) {
+	quote, err := orderbook.GetCashOutQuote(req.MatchId, req.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toQuoteProto(quote), nil
+}
+
+func (s *orderbookServer) CashOut(ctx context.Context, req *orderbookpb.CashOutRequest) (*orderbookpb.CashOutResponse, error) {
+	result, err := orderbook.CashOut(req.MatchId, req.UserId, int(req.ToleranceTicks))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &orderbookpb.CashOutResponse{
+		Quote:    toQuoteProto(result.Quote),
+		Report:   toReportProto(result.Report),
+		Position: toPositionProto(result.Position),
+	}, nil
+}
+
+func toQuoteProto(quote orderbook.CashOutQuote) *orderbookpb.CashOutQuote {
+	return &orderbookpb.CashOutQuote{
+		MatchId:   quote.MatchID,
+		UserId:    quote.UserID,
+		TeamId:    quote.TeamID,
+		Side:      quote.Side,
+		Price:     quote.Price.String(),
+		Stake:     quote.Stake.String(),
+		Available: quote.Available.String(),
+		Profit:    quote.Profit.String(),
+		After:     toPositionProto(quote.After),
+	}
+}
+
+func toWalletProto(wallet orderbook.Wallet) *orderbookpb.WalletResponse {
+	return &orderbookpb.WalletResponse{
+		UserId:    wallet.UserID,
+		Available: wallet.Available.String(),
+		Reserved:  wallet.Reserved.String(),
+	}
+}
+
+// PlaceOrder places a back ("bid") or lay ("ask") of a runner. Price is
+// decimal odds, the lowest a back will take and the highest a lay will give.
+func (s *orderbookServer) PlaceOrder(ctx context.Context, req *orderbookpb.PlaceOrderRequest) (*orderbookpb.ExecutionReport, error) {
+	order := orderbook.Order{
+		ID:          req.OrderId,
+		MatchID:     req.MatchId,
+		TeamID:      req.TeamId,
+		UserID:      req.UserId,
+		Side:        req.Side,
+		TimeInForce: req.TimeInForce,
+		Type:        req.Type,
+	}
+	if req.ExpiresAt > 0 {
+		order.ExpiresAt = time.Unix(req.ExpiresAt, 0)
+	}
+
+	var err error
+	if req.Price != "" {
+		if order.Price, err = orderbook.ParseOdds(req.Price); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "price: %v", err)
+		}
+	}
+	if req.WorstPrice != "" {
+		if order.WorstPrice, err = orderbook.ParseOdds(req.WorstPrice); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "worst_price: %v", err)
+		}
+	}
+	if order.Quantity, err = orderbook.ParseMoney(req.Quantity); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "quantity: %v", err)
+	}
+
+	report, err := orderbook.PlaceOrder(order)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return toReportProto(report), nil
+}
+
+func toReportProto(report orderbook.ExecutionReport) *orderbookpb.ExecutionReport {
+	fills := make([]*orderbookpb.Fill, 0, len(report.Fills))
+	for _, fill := range report.Fills {
+		fills = append(fills, &orderbookpb.Fill{
+			CounterpartyOrderId:  fill.CounterpartyOrderID,
+			Price:                fill.Price.String(),
+			Quantity:             fill.Quantity.String(),
+			MatchType:            fill.MatchType,
+			Liability:            fill.Liability.String(),
+			CounterpartyOrderIds: fill.CounterpartyOrderIDs,
+		})
+	}
+	return &orderbookpb.ExecutionReport{
+		OrderId:            report.OrderID,
+		Status:             report.Status,
+		Fills:              fills,
+		RestingQuantity:    report.Resting.String(),
+		Reason:             report.Reason,
+		RejectCode:         string(report.RejectCode),
+		SelfTradePrevented: report.SelfTradePrevented.String(),
+	}
+}
+
+func (s *orderbookServer) CancelOrder(ctx context.Context, req *orderbookpb.CancelOrderRequest) (*orderbookpb.CancelOrderResponse, error) {
+	remaining, err := orderbook.CancelOrder(req.MatchId, req.OrderId, req.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &orderbookpb.CancelOrderResponse{
+		OrderId:           req.OrderId,
+		RemainingQuantity: remaining.String(),
+	}, nil
+}
+
+func (s *orderbookServer) AmendOrder(ctx context.Context, req *orderbookpb.AmendOrderRequest) (*orderbookpb.AmendOrderResponse, error) {
+	price, err := orderbook.ParseOdds(req.Price)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "price: %v", err)
+	}
+	quantity, err := orderbook.ParseMoney(req.Quantity)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "quantity: %v", err)
+	}
+
+	amended, err := orderbook.AmendOrder(req.MatchId, req.OrderId, req.UserId, price, quantity)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &orderbookpb.AmendOrderResponse{
+		OrderId:           amended.ID,
+		Price:             amended.Price.String(),
+		RemainingQuantity: amended.Quantity.String(),
+	}, nil
+}
+
+func (s *orderbookServer) SetSelfTradePolicy(ctx context.Context, req *orderbookpb.SelfTradePolicyRequest) (*orderbookpb.SelfTradePolicyResponse, error) {
+	policy := orderbook.SelfTradePolicy(req.Policy)
+	if err := orderbook.SetSelfTradePolicy(req.MatchId, policy); err != nil {
+		return nil, toStatusError(err)
+	}
+	return &orderbookpb.SelfTradePolicyResponse{
+		MatchId: req.MatchId,
+		Policy:  string(policy),
+	}, nil
+}
+
+func (s *orderbookServer) SetSurplusPolicy(ctx context.Context, req *orderbookpb.SurplusPolicyRequest) (*orderbookpb.SurplusPolicyResponse, error) {
+	policy := orderbook.SurplusPolicy(req.Policy)
+	if err := orderbook.SetSurplusPolicy(req.MatchId, policy); err != nil {
+		return nil, toStatusError(err)
+	}
+	return &orderbookpb.SurplusPolicyResponse{
+		MatchId: req.MatchId,
+		Policy:  string(policy),
+	}, nil
+}
+
+func (s *orderbookServer) SetMaxExposure(ctx context.Context, req *orderbookpb.MaxExposureRequest) (*orderbookpb.MaxExposureResponse, error) {
+	limit, err := orderbook.ParseMoney(req.MaxExposure)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "max_exposure: %v", err)
+	}
+	if err := orderbook.SetMaxExposure(req.MatchId, limit); err != nil {
+		return nil, toStatusError(err)
+	}
+	return &orderbookpb.MaxExposureResponse{
+		MatchId:     req.MatchId,
+		MaxExposure: limit.String(),
+	}, nil
+}
+
+// toStatusError maps engine errors onto gRPC status codes.
+func toStatusError(err error) error {
+	switch {
+	case errors.Is(err, orderbook.ErrOrderNotFound), errors.Is(err, orderbook.ErrMatchNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, orderbook.ErrMatchExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, orderbook.ErrMarketBusy), errors.Is(err, orderbook.ErrEngineHalted):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, orderbook.ErrInvalidTransition), errors.Is(err, orderbook.ErrMarketNotOpen),
+		errors.Is(err, orderbook.ErrSettlementRequired), errors.Is(err, orderbook.ErrAlreadySettled),
+		errors.Is(err, orderbook.ErrNotSettled), errors.Is(err, orderbook.ErrMarketVoided),
+		errors.Is(err, orderbook.ErrInsufficientFunds), errors.Is(err, orderbook.ErrExposureLimit),
+		errors.Is(err, orderbook.ErrNothingToCashOut), errors.Is(err, orderbook.ErrNoLiquidity),
+		errors.Is(err, orderbook.ErrOrderRejected), errors.Is(err, orderbook.ErrOrderExpired):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, orderbook.ErrNotOrderOwner):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, orderbook.ErrInvalidAmendment), errors.Is(err, orderbook.ErrOffLadder),
+		errors.Is(err, orderbook.ErrInvalidSelfTradePolicy), errors.Is(err, orderbook.ErrInvalidSurplusPolicy),
+		errors.Is(err, orderbook.ErrInvalidMarketState), errors.Is(err, orderbook.ErrUnknownRunner),
+		errors.Is(err, orderbook.ErrMissingUser), errors.Is(err, orderbook.ErrInvalidAmount),
+		errors.Is(err, orderbook.ErrInvalidTolerance), errors.Is(err, orderbook.ErrInvalidRunners):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
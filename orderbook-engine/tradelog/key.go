@@ -0,0 +1,73 @@
+package tradelog
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// GenerateKey writes a new hex-encoded ed25519 seed to path, readable by
+// its owner only, and the public key to pubPath for verifiers. It never
+// overwrites an existing key. Keep the seed away from the log it signs:
+// whoever can change the log must not be able to re-sign it.
+func GenerateKey(path, pubPath string) (ed25519.PublicKey, error) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.WriteString(hex.EncodeToString(key.Seed()) + "\n"); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(pubPath, []byte(hex.EncodeToString(pub)+"\n"), 0o644); err != nil {
+		return nil, err
+	}
+	return pub, nil
+}
+
+// LoadKey reads the hex-encoded ed25519 seed at path.
+func LoadKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := decodeKey(path, data, ed25519.SeedSize)
+	if err != nil {
+		return nil, err
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// LoadPublicKey reads a hex-encoded ed25519 public key.
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := decodeKey(path, data, ed25519.PublicKeySize)
+	if err != nil {
+		return nil, err
+	}
+	return ed25519.PublicKey(pub), nil
+}
+
+func decodeKey(path string, data []byte, size int) ([]byte, error) {
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(key) != size {
+		return nil, fmt.Errorf("%s: key is %d bytes, want %d", path, len(key), size)
+	}
+	return key, nil
+}
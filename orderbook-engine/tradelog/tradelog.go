@@ -0,0 +1,294 @@
+// Package tradelog is an append-only, hash-chained log. Every entry carries
+// the SHA-256 of the entry before it, so changing, removing or reordering
+// any entry breaks the chain from that point on. Signed checkpoints vouch
+// for the chain up to them, so the log cannot be rewritten wholesale
+// without the signing key. Each checkpoint is also written, signed, to a
+// head file beside the log, so cutting the log short of its last
+// checkpoint shows up too.
+package tradelog
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Entry kinds.
+const (
+	KindRecord     = "record"
+	KindCheckpoint = "checkpoint"
+)
+
+// GenesisHash is the previous hash of the first entry.
+var GenesisHash = hex.EncodeToString(make([]byte, sha256.Size))
+
+// Entry is one line of the log.
+type Entry struct {
+	Seq       uint64          `json:"seq"`
+	Kind      string          `json:"kind"`
+	Time      time.Time       `json:"time"`
+	Data      json.RawMessage `json:"data,omitempty"`      // records only
+	Signature string          `json:"signature,omitempty"` // checkpoints only: ed25519 over the chain head, hex
+	PrevHash  string          `json:"prev_hash"`
+	Hash      string          `json:"hash"` // SHA-256 of every other field, hex
+}
+
+// digest hashes every field of the entry except Hash itself.
+func (e Entry) digest() string {
+	h := sha256.New()
+	for _, field := range [][]byte{
+		[]byte(e.PrevHash),
+		[]byte(strconv.FormatUint(e.Seq, 10)),
+		[]byte(e.Kind),
+		[]byte(e.Time.UTC().Format(time.RFC3339Nano)),
+		e.Data,
+		[]byte(e.Signature),
+	} {
+		h.Write(field)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// checkpointMessage is what a checkpoint at seq signs: the hash of the
+// entry before it, which commits to the whole chain up to there.
+func checkpointMessage(seq uint64, head string) []byte {
+	return []byte("tradelog checkpoint " + strconv.FormatUint(seq, 10) + " " + head)
+}
+
+// Head is the last checkpoint of a log, kept in its own file. A log that
+// ends before its head has lost entries. Whoever must detect a log and
+// its head being rolled back together should keep a copy of each head.
+type Head struct {
+	Seq       uint64 `json:"seq"`
+	Hash      string `json:"hash"`
+	Signature string `json:"signature"` // ed25519 over Seq and Hash, hex
+}
+
+func headMessage(seq uint64, hash string) []byte {
+	return []byte("tradelog head " + strconv.FormatUint(seq, 10) + " " + hash)
+}
+
+// HeadPath is where the head of the log at path is kept.
+func HeadPath(path string) string { return path + ".head" }
+
+// ReadHead reads a head file and checks its signature against pub. A log
+// that has never been checkpointed has no head: ReadHead then returns an
+// error satisfying errors.Is(err, os.ErrNotExist).
+func ReadHead(path string, pub ed25519.PublicKey) (*Head, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var head Head
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, fmt.Errorf("%w: head %s is unreadable: %v", ErrTampered, path, err)
+	}
+	sig, err := hex.DecodeString(head.Signature)
+	if err != nil || !ed25519.Verify(pub, headMessage(head.Seq, head.Hash), sig) {
+		return nil, fmt.Errorf("%w: head %s has a bad signature", ErrTampered, path)
+	}
+	return &head, nil
+}
+
+// writeHead replaces the head file with one for the entry at seq. The file
+// is synced before it replaces the old one, so a crash leaves either.
+func writeHead(path string, key ed25519.PrivateKey, seq uint64, hash string) error {
+	data, err := json.Marshal(Head{
+		Seq:       seq,
+		Hash:      hash,
+		Signature: hex.EncodeToString(ed25519.Sign(key, headMessage(seq, hash))),
+	})
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".head-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Log appends entries to a file. It is safe for concurrent use. Records
+// are signed when the writer calls Checkpoint, typically once for each
+// batch it appends.
+type Log struct {
+	mu       sync.Mutex
+	file     *os.File
+	headPath string
+	key      ed25519.PrivateKey
+	seq      uint64
+	head     string
+	last     *Entry // last record, if any
+	pending  int    // records since the last checkpoint
+}
+
+// Open opens the log at path for appending, creating it if needed. The
+// existing chain is verified first, against its head file too, so a log
+// that has been tampered with or cut short is never extended. A final
+// line torn by a crash is dropped.
+func Open(path string, key ed25519.PrivateKey) (*Log, error) {
+	pub := key.Public().(ed25519.PublicKey)
+	head, err := ReadHead(HeadPath(path), pub)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	l := &Log{file: file, headPath: HeadPath(path), key: key, head: GenesisHash}
+
+	var end int64
+	v := newVerifier(pub, head)
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(bytes.TrimSpace(line)) > 0 {
+				log.Printf("Trade log %s: dropping torn entry at offset %d", path, end)
+			}
+			break
+		}
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		entry, err := v.next(line)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		end += int64(len(line))
+		if entry.Kind == KindRecord {
+			l.last = &entry
+			l.pending++
+		} else {
+			l.pending = 0
+		}
+	}
+	if err := v.finish(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	l.seq, l.head = v.seq, v.head
+
+	if err := file.Truncate(end); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.Seek(end, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Last returns the last record in the log.
+func (l *Log) Last() (Entry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.last == nil {
+		return Entry{}, false
+	}
+	return *l.last, true
+}
+
+// Append writes v as the next record. It is unsigned until the next
+// checkpoint.
+func (l *Log) Append(v any) (Entry, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return Entry{}, err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, err := l.write(Entry{Kind: KindRecord, Data: data})
+	if err != nil {
+		return Entry{}, err
+	}
+	l.last = &entry
+	l.pending++
+	return entry, nil
+}
+
+// Checkpoint signs the chain as it stands, syncs the file and then moves
+// the head file up to the new checkpoint. Once it returns, every record
+// appended before the call is signed and on disk, and the log can no
+// longer be cut short of them unnoticed. It writes nothing when there are
+// no records since the last checkpoint.
+func (l *Log) Checkpoint() (Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.pending == 0 {
+		return Entry{}, nil
+	}
+	return l.checkpoint()
+}
+
+func (l *Log) checkpoint() (Entry, error) {
+	seq := l.seq + 1
+	entry, err := l.write(Entry{
+		Kind:      KindCheckpoint,
+		Signature: hex.EncodeToString(ed25519.Sign(l.key, checkpointMessage(seq, l.head))),
+	})
+	if err != nil {
+		return Entry{}, err
+	}
+	l.pending = 0
+	if err := l.file.Sync(); err != nil {
+		return entry, err
+	}
+	return entry, writeHead(l.headPath, l.key, entry.Seq, entry.Hash)
+}
+
+// write chains entry onto the log; the caller holds mu.
+func (l *Log) write(entry Entry) (Entry, error) {
+	entry.Seq = l.seq + 1
+	entry.Time = time.Now().UTC()
+	entry.PrevHash = l.head
+	entry.Hash = entry.digest()
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return Entry{}, err
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return Entry{}, err
+	}
+	l.seq, l.head = entry.Seq, entry.Hash
+	return entry, nil
+}
+
+// Close checkpoints whatever is unsigned and closes the file.
+func (l *Log) Close() error {
+	if _, err := l.Checkpoint(); err != nil {
+		l.file.Close()
+		return err
+	}
+	return l.file.Close()
+}
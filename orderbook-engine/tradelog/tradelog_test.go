@@ -0,0 +1,192 @@
+package tradelog
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeLog writes records to a new log, checkpointing after each batch as
+// the engine does before acknowledging a command, and returns the log's
+// lines and head.
+func writeLog(t *testing.T, key ed25519.PrivateKey, batches ...[]string) (string, [][]byte, []byte) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "trades.log")
+	l, err := Open(path, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, batch := range batches {
+		for _, record := range batch {
+			if _, err := l.Append(record); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := l.Checkpoint(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := os.ReadFile(HeadPath(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path, bytes.SplitAfter(data, []byte("\n"))[:bytes.Count(data, []byte("\n"))], head
+}
+
+// verify checks lines against the head, as verify-trade-log does.
+func verify(t *testing.T, pub ed25519.PublicKey, lines [][]byte, headData []byte) (Report, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "head")
+	if err := os.WriteFile(path, headData, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	head, err := ReadHead(path, pub)
+	if err != nil {
+		return Report{}, err
+	}
+	return Verify(bytes.NewReader(bytes.Join(lines, nil)), pub, head)
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Entries: 1 a, 2 b, 3 checkpoint, 4 c, 5 d, 6 checkpoint.
+	_, lines, head := writeLog(t, key, []string{"a", "b"}, []string{"c", "d"})
+	if len(lines) != 6 {
+		t.Fatalf("%d entries", len(lines))
+	}
+	report, err := verify(t, pub, lines, head)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Records != 4 || report.Checkpoints != 2 || report.Unsigned != 0 {
+		t.Errorf("report %+v", report)
+	}
+
+	_, earlier, earlierHead := writeLog(t, key, []string{"a", "b"})
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		lines [][]byte
+		head  []byte
+		pub   ed25519.PublicKey
+	}{
+		{"modified", replace(lines, 1, bytes.Replace(lines[1], []byte(`"b"`), []byte(`"x"`), 1)), head, pub},
+		{"deleted", remove(lines, 1), head, pub},
+		{"reordered", swap(lines, 0, 1), head, pub},
+		{"truncated to an earlier checkpoint", lines[:3], head, pub},
+		{"truncated mid-batch", lines[:4], head, pub},
+		{"everything deleted", nil, head, pub},
+		{"head from another log", lines, earlierHead, pub},
+		{"head moved back", lines[:3], modifiedHead(t, head, lines[2]), pub},
+		{"another key", lines, head, otherPub},
+		{"spliced from another log", append(clone(earlier), lines[3:]...), head, pub},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := verify(t, tt.pub, tt.lines, tt.head); !errors.Is(err, ErrTampered) {
+				t.Errorf("got %v, want %v", err, ErrTampered)
+			}
+		})
+	}
+}
+
+// TestOpenRefusesTruncatedLog checks that the engine will not extend a log
+// that has lost signed entries, but drops unacknowledged ones after its
+// head without complaint.
+func TestOpenRefusesTruncatedLog(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, lines, _ := writeLog(t, key, []string{"a", "b"}, []string{"c"})
+
+	// A record written after the head and then lost is fine.
+	l, err := Open(path, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Append("d"); err != nil {
+		t.Fatal(err)
+	}
+	l.file.Close()
+	if err := os.WriteFile(path, bytes.Join(lines, nil), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	l, err = Open(path, key)
+	if err != nil {
+		t.Fatalf("dropping an unacknowledged record: %v", err)
+	}
+	l.file.Close()
+
+	if err := os.WriteFile(path, bytes.Join(lines[:3], nil), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path, key); !errors.Is(err, ErrTampered) {
+		t.Errorf("opening a truncated log: %v", err)
+	}
+	if err := os.Remove(HeadPath(path)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path, key); !errors.Is(err, ErrTampered) {
+		t.Errorf("opening a checkpointed log without its head: %v", err)
+	}
+}
+
+// modifiedHead is head moved to the checkpoint on line, signed with no key:
+// the signature no longer matches.
+func modifiedHead(t *testing.T, head, line []byte) []byte {
+	t.Helper()
+	var e struct {
+		Seq  uint64
+		Hash string
+	}
+	if err := json.Unmarshal(line, &e); err != nil {
+		t.Fatal(err)
+	}
+	var h Head
+	if err := json.Unmarshal(head, &h); err != nil {
+		t.Fatal(err)
+	}
+	h.Seq, h.Hash = e.Seq, e.Hash
+	data, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func clone(lines [][]byte) [][]byte { return append([][]byte(nil), lines...) }
+
+func replace(lines [][]byte, i int, line []byte) [][]byte {
+	out := clone(lines)
+	out[i] = line
+	return out
+}
+
+func remove(lines [][]byte, i int) [][]byte {
+	return append(clone(lines[:i]), lines[i+1:]...)
+}
+
+func swap(lines [][]byte, i, j int) [][]byte {
+	out := clone(lines)
+	out[i], out[j] = out[j], out[i]
+	return out
+}
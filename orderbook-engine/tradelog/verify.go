@@ -0,0 +1,127 @@
+package tradelog
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrTampered is returned when the log is not the chain it claims to be:
+// an entry was changed, removed, inserted or moved, or a checkpoint was
+// not signed by the expected key.
+var ErrTampered = errors.New("trade log has been tampered with")
+
+// Report summarises a verified log.
+type Report struct {
+	Entries        uint64 `json:"entries"`
+	Records        uint64 `json:"records"`
+	Checkpoints    uint64 `json:"checkpoints"`
+	LastCheckpoint uint64 `json:"last_checkpoint"` // seq of the last signed checkpoint, 0 if none
+	Unsigned       uint64 `json:"unsigned"`        // records after it, written but never acknowledged
+	Head           string `json:"head"`            // hash of the last entry
+}
+
+// Verify reads a whole log and checks every link of the chain and every
+// checkpoint signature against pub. head is the log's signed head, from
+// ReadHead, or nil for a log that has never been checkpointed; the log
+// must reach it with the same hash. Verify stops at the first broken
+// entry.
+func Verify(r io.Reader, pub ed25519.PublicKey, head *Head) (Report, error) {
+	return Scan(r, pub, head, nil)
+}
+
+// Scan verifies a log as Verify does and passes each record to fn, if not
+// nil, once the chain up to it has checked out. It stops at the first
+// error fn returns.
+func Scan(r io.Reader, pub ed25519.PublicKey, head *Head, fn func(Entry) error) (Report, error) {
+	v := newVerifier(pub, head)
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(line) > 0 {
+				return v.report, fmt.Errorf("%w: entry %d is incomplete", ErrTampered, v.seq+1)
+			}
+			return v.report, v.finish()
+		}
+		if err != nil {
+			return v.report, err
+		}
+		entry, err := v.next(line)
+		if err != nil {
+			return v.report, err
+		}
+		if fn != nil && entry.Kind == KindRecord {
+			if err := fn(entry); err != nil {
+				return v.report, err
+			}
+		}
+	}
+}
+
+// verifier walks a chain one entry at a time.
+type verifier struct {
+	pub    ed25519.PublicKey
+	signed *Head // the chain must reach it
+	seq    uint64
+	head   string
+	report Report
+}
+
+func newVerifier(pub ed25519.PublicKey, signed *Head) *verifier {
+	return &verifier{pub: pub, signed: signed, head: GenesisHash, report: Report{Head: GenesisHash}}
+}
+
+// finish checks, once the chain has ended, that nothing it was signed up
+// to is missing.
+func (v *verifier) finish() error {
+	switch {
+	case v.signed == nil && v.report.Checkpoints > 0:
+		return fmt.Errorf("%w: the log is checkpointed but has no head", ErrTampered)
+	case v.signed != nil && v.seq < v.signed.Seq:
+		return fmt.Errorf("%w: the log ends at entry %d, before its head at entry %d", ErrTampered, v.seq, v.signed.Seq)
+	}
+	return nil
+}
+
+// next checks that line is the entry that follows the chain so far.
+func (v *verifier) next(line []byte) (Entry, error) {
+	var e Entry
+	if err := json.Unmarshal(line, &e); err != nil {
+		return Entry{}, fmt.Errorf("%w: entry %d is unreadable: %v", ErrTampered, v.seq+1, err)
+	}
+	switch {
+	case e.Seq != v.seq+1:
+		return Entry{}, fmt.Errorf("%w: entry %d follows entry %d", ErrTampered, e.Seq, v.seq)
+	case e.PrevHash != v.head:
+		return Entry{}, fmt.Errorf("%w: entry %d does not follow the hash of entry %d", ErrTampered, e.Seq, v.seq)
+	case e.Hash != e.digest():
+		return Entry{}, fmt.Errorf("%w: entry %d does not match its hash", ErrTampered, e.Seq)
+	case v.signed != nil && e.Seq == v.signed.Seq && e.Hash != v.signed.Hash:
+		return Entry{}, fmt.Errorf("%w: entry %d is not the head", ErrTampered, e.Seq)
+	}
+
+	switch e.Kind {
+	case KindRecord:
+		v.report.Records++
+		v.report.Unsigned++
+	case KindCheckpoint:
+		sig, err := hex.DecodeString(e.Signature)
+		if err != nil || !ed25519.Verify(v.pub, checkpointMessage(e.Seq, e.PrevHash), sig) {
+			return Entry{}, fmt.Errorf("%w: checkpoint %d has a bad signature", ErrTampered, e.Seq)
+		}
+		v.report.Checkpoints++
+		v.report.LastCheckpoint = e.Seq
+		v.report.Unsigned = 0
+	default:
+		return Entry{}, fmt.Errorf("%w: entry %d has unknown kind %q", ErrTampered, e.Seq, e.Kind)
+	}
+	v.seq, v.head = e.Seq, e.Hash
+	v.report.Entries++
+	v.report.Head = e.Hash
+	return e, nil
+}
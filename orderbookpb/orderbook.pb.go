@@ -22,12 +22,17 @@ const (
 )
 
 type MatchRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	MatchId       string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
-	TeamA         string                 `protobuf:"bytes,2,opt,name=team_a,json=teamA,proto3" json:"team_a,omitempty"`
-	TeamB         string                 `protobuf:"bytes,3,opt,name=team_b,json=teamB,proto3" json:"team_b,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	MatchId         string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	TeamA           string                 `protobuf:"bytes,2,opt,name=team_a,json=teamA,proto3" json:"team_a,omitempty"`
+	TeamB           string                 `protobuf:"bytes,3,opt,name=team_b,json=teamB,proto3" json:"team_b,omitempty"`
+	StartTime       int64                  `protobuf:"varint,4,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	SelfTradePolicy string                 `protobuf:"bytes,5,opt,name=self_trade_policy,json=selfTradePolicy,proto3" json:"self_trade_policy,omitempty"`
+	SurplusPolicy   string                 `protobuf:"bytes,6,opt,name=surplus_policy,json=surplusPolicy,proto3" json:"surplus_policy,omitempty"`
+	Runners         []string               `protobuf:"bytes,7,rep,name=runners,proto3" json:"runners,omitempty"`
+	MaxExposure     string                 `protobuf:"bytes,8,opt,name=max_exposure,json=maxExposure,proto3" json:"max_exposure,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *MatchRequest) Reset() {
@@ -81,6 +86,41 @@ func (x *MatchRequest) GetTeamB() string {
 	return ""
 }
 
+func (x *MatchRequest) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *MatchRequest) GetSelfTradePolicy() string {
+	if x != nil {
+		return x.SelfTradePolicy
+	}
+	return ""
+}
+
+func (x *MatchRequest) GetSurplusPolicy() string {
+	if x != nil {
+		return x.SurplusPolicy
+	}
+	return ""
+}
+
+func (x *MatchRequest) GetRunners() []string {
+	if x != nil {
+		return x.Runners
+	}
+	return nil
+}
+
+func (x *MatchRequest) GetMaxExposure() string {
+	if x != nil {
+		return x.MaxExposure
+	}
+	return ""
+}
+
 type RegisterMatchResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
@@ -125,19 +165,2253 @@ func (x *RegisterMatchResponse) GetStatus() string {
 	return ""
 }
 
-var File_proto_orderbook_proto protoreflect.FileDescriptor
+type TransitionMarketRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MatchId       string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	State         string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_proto_orderbook_proto_rawDesc = "" +
-	"\n" +
-	"\x15proto/orderbook.proto\x12\torderbook\"W\n" +
-	"\fMatchRequest\x12\x19\n" +
-	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x12\x15\n" +
-	"\x06team_a\x18\x02 \x01(\tR\x05teamA\x12\x15\n" +
-	"\x06team_b\x18\x03 \x01(\tR\x05teamB\"/\n" +
-	"\x15RegisterMatchResponse\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status2^\n" +
-	"\x10OrderbookService\x12J\n" +
-	"\rRegisterMatch\x12\x17.orderbook.MatchRequest\x1a .orderbook.RegisterMatchResponseB-Z+github.com/amithshubhan/Bet_Now/orderbookpbb\x06proto3"
+func (x *TransitionMarketRequest) Reset() {
+	*x = TransitionMarketRequest{}
+	mi := &file_proto_orderbook_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransitionMarketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransitionMarketRequest) ProtoMessage() {}
+
+func (x *TransitionMarketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransitionMarketRequest.ProtoReflect.Descriptor instead.
+func (*TransitionMarketRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TransitionMarketRequest) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *TransitionMarketRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *TransitionMarketRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type MarketStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MatchId       string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarketStateRequest) Reset() {
+	*x = MarketStateRequest{}
+	mi := &file_proto_orderbook_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarketStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarketStateRequest) ProtoMessage() {}
+
+func (x *MarketStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarketStateRequest.ProtoReflect.Descriptor instead.
+func (*MarketStateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *MarketStateRequest) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+type MarketStateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MatchId       string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	State         string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	PreviousState string                 `protobuf:"bytes,3,opt,name=previous_state,json=previousState,proto3" json:"previous_state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarketStateResponse) Reset() {
+	*x = MarketStateResponse{}
+	mi := &file_proto_orderbook_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarketStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarketStateResponse) ProtoMessage() {}
+
+func (x *MarketStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarketStateResponse.ProtoReflect.Descriptor instead.
+func (*MarketStateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MarketStateResponse) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *MarketStateResponse) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *MarketStateResponse) GetPreviousState() string {
+	if x != nil {
+		return x.PreviousState
+	}
+	return ""
+}
+
+type SettleMatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MatchId       string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	WinningTeamId string                 `protobuf:"bytes,2,opt,name=winning_team_id,json=winningTeamId,proto3" json:"winning_team_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SettleMatchRequest) Reset() {
+	*x = SettleMatchRequest{}
+	mi := &file_proto_orderbook_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SettleMatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SettleMatchRequest) ProtoMessage() {}
+
+func (x *SettleMatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SettleMatchRequest.ProtoReflect.Descriptor instead.
+func (*SettleMatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SettleMatchRequest) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *SettleMatchRequest) GetWinningTeamId() string {
+	if x != nil {
+		return x.WinningTeamId
+	}
+	return ""
+}
+
+type VoidMatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MatchId       string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VoidMatchRequest) Reset() {
+	*x = VoidMatchRequest{}
+	mi := &file_proto_orderbook_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VoidMatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoidMatchRequest) ProtoMessage() {}
+
+func (x *VoidMatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoidMatchRequest.ProtoReflect.Descriptor instead.
+func (*VoidMatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *VoidMatchRequest) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *VoidMatchRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type UserSettlement struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Staked        string                 `protobuf:"bytes,2,opt,name=staked,proto3" json:"staked,omitempty"`
+	Won           string                 `protobuf:"bytes,3,opt,name=won,proto3" json:"won,omitempty"`
+	Lost          string                 `protobuf:"bytes,4,opt,name=lost,proto3" json:"lost,omitempty"`
+	Net           string                 `protobuf:"bytes,5,opt,name=net,proto3" json:"net,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserSettlement) Reset() {
+	*x = UserSettlement{}
+	mi := &file_proto_orderbook_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserSettlement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserSettlement) ProtoMessage() {}
+
+func (x *UserSettlement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserSettlement.ProtoReflect.Descriptor instead.
+func (*UserSettlement) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UserSettlement) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserSettlement) GetStaked() string {
+	if x != nil {
+		return x.Staked
+	}
+	return ""
+}
+
+func (x *UserSettlement) GetWon() string {
+	if x != nil {
+		return x.Won
+	}
+	return ""
+}
+
+func (x *UserSettlement) GetLost() string {
+	if x != nil {
+		return x.Lost
+	}
+	return ""
+}
+
+func (x *UserSettlement) GetNet() string {
+	if x != nil {
+		return x.Net
+	}
+	return ""
+}
+
+type SettlementReport struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MatchId       string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	WinningTeamId string                 `protobuf:"bytes,2,opt,name=winning_team_id,json=winningTeamId,proto3" json:"winning_team_id,omitempty"`
+	Users         []*UserSettlement      `protobuf:"bytes,3,rep,name=users,proto3" json:"users,omitempty"`
+	HouseNet      string                 `protobuf:"bytes,4,opt,name=house_net,json=houseNet,proto3" json:"house_net,omitempty"`
+	SettledAt     int64                  `protobuf:"varint,5,opt,name=settled_at,json=settledAt,proto3" json:"settled_at,omitempty"`
+	Voided        bool                   `protobuf:"varint,6,opt,name=voided,proto3" json:"voided,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SettlementReport) Reset() {
+	*x = SettlementReport{}
+	mi := &file_proto_orderbook_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SettlementReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SettlementReport) ProtoMessage() {}
+
+func (x *SettlementReport) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SettlementReport.ProtoReflect.Descriptor instead.
+func (*SettlementReport) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SettlementReport) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *SettlementReport) GetWinningTeamId() string {
+	if x != nil {
+		return x.WinningTeamId
+	}
+	return ""
+}
+
+func (x *SettlementReport) GetUsers() []*UserSettlement {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *SettlementReport) GetHouseNet() string {
+	if x != nil {
+		return x.HouseNet
+	}
+	return ""
+}
+
+func (x *SettlementReport) GetSettledAt() int64 {
+	if x != nil {
+		return x.SettledAt
+	}
+	return 0
+}
+
+func (x *SettlementReport) GetVoided() bool {
+	if x != nil {
+		return x.Voided
+	}
+	return false
+}
+
+type CancelOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MatchId       string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
+	mi := &file_proto_orderbook_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderRequest) ProtoMessage() {}
+
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CancelOrderRequest) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type PlaceOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	MatchId       string                 `protobuf:"bytes,2,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	TeamId        string                 `protobuf:"bytes,3,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Side          string                 `protobuf:"bytes,5,opt,name=side,proto3" json:"side,omitempty"`
+	Price         string                 `protobuf:"bytes,6,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity      string                 `protobuf:"bytes,7,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	TimeInForce   string                 `protobuf:"bytes,8,opt,name=time_in_force,json=timeInForce,proto3" json:"time_in_force,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,9,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Type          string                 `protobuf:"bytes,10,opt,name=type,proto3" json:"type,omitempty"`
+	WorstPrice    string                 `protobuf:"bytes,11,opt,name=worst_price,json=worstPrice,proto3" json:"worst_price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlaceOrderRequest) Reset() {
+	*x = PlaceOrderRequest{}
+	mi := &file_proto_orderbook_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlaceOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceOrderRequest) ProtoMessage() {}
+
+func (x *PlaceOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceOrderRequest.ProtoReflect.Descriptor instead.
+func (*PlaceOrderRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PlaceOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetTeamId() string {
+	if x != nil {
+		return x.TeamId
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetTimeInForce() string {
+	if x != nil {
+		return x.TimeInForce
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *PlaceOrderRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetWorstPrice() string {
+	if x != nil {
+		return x.WorstPrice
+	}
+	return ""
+}
+
+type Fill struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	CounterpartyOrderId  string                 `protobuf:"bytes,1,opt,name=counterparty_order_id,json=counterpartyOrderId,proto3" json:"counterparty_order_id,omitempty"`
+	Price                string                 `protobuf:"bytes,2,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity             string                 `protobuf:"bytes,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	MatchType            string                 `protobuf:"bytes,4,opt,name=match_type,json=matchType,proto3" json:"match_type,omitempty"`
+	Liability            string                 `protobuf:"bytes,5,opt,name=liability,proto3" json:"liability,omitempty"`
+	CounterpartyOrderIds []string               `protobuf:"bytes,6,rep,name=counterparty_order_ids,json=counterpartyOrderIds,proto3" json:"counterparty_order_ids,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Fill) Reset() {
+	*x = Fill{}
+	mi := &file_proto_orderbook_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Fill) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Fill) ProtoMessage() {}
+
+func (x *Fill) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Fill.ProtoReflect.Descriptor instead.
+func (*Fill) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Fill) GetCounterpartyOrderId() string {
+	if x != nil {
+		return x.CounterpartyOrderId
+	}
+	return ""
+}
+
+func (x *Fill) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *Fill) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+func (x *Fill) GetMatchType() string {
+	if x != nil {
+		return x.MatchType
+	}
+	return ""
+}
+
+func (x *Fill) GetLiability() string {
+	if x != nil {
+		return x.Liability
+	}
+	return ""
+}
+
+func (x *Fill) GetCounterpartyOrderIds() []string {
+	if x != nil {
+		return x.CounterpartyOrderIds
+	}
+	return nil
+}
+
+type ExecutionReport struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	OrderId            string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status             string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Fills              []*Fill                `protobuf:"bytes,3,rep,name=fills,proto3" json:"fills,omitempty"`
+	RestingQuantity    string                 `protobuf:"bytes,4,opt,name=resting_quantity,json=restingQuantity,proto3" json:"resting_quantity,omitempty"`
+	Reason             string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	RejectCode         string                 `protobuf:"bytes,6,opt,name=reject_code,json=rejectCode,proto3" json:"reject_code,omitempty"`
+	SelfTradePrevented string                 `protobuf:"bytes,7,opt,name=self_trade_prevented,json=selfTradePrevented,proto3" json:"self_trade_prevented,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ExecutionReport) Reset() {
+	*x = ExecutionReport{}
+	mi := &file_proto_orderbook_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecutionReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecutionReport) ProtoMessage() {}
+
+func (x *ExecutionReport) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecutionReport.ProtoReflect.Descriptor instead.
+func (*ExecutionReport) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ExecutionReport) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *ExecutionReport) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ExecutionReport) GetFills() []*Fill {
+	if x != nil {
+		return x.Fills
+	}
+	return nil
+}
+
+func (x *ExecutionReport) GetRestingQuantity() string {
+	if x != nil {
+		return x.RestingQuantity
+	}
+	return ""
+}
+
+func (x *ExecutionReport) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ExecutionReport) GetRejectCode() string {
+	if x != nil {
+		return x.RejectCode
+	}
+	return ""
+}
+
+func (x *ExecutionReport) GetSelfTradePrevented() string {
+	if x != nil {
+		return x.SelfTradePrevented
+	}
+	return ""
+}
+
+type CancelOrderResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	OrderId           string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	RemainingQuantity string                 `protobuf:"bytes,2,opt,name=remaining_quantity,json=remainingQuantity,proto3" json:"remaining_quantity,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CancelOrderResponse) Reset() {
+	*x = CancelOrderResponse{}
+	mi := &file_proto_orderbook_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderResponse) ProtoMessage() {}
+
+func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderResponse.ProtoReflect.Descriptor instead.
+func (*CancelOrderResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CancelOrderResponse) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *CancelOrderResponse) GetRemainingQuantity() string {
+	if x != nil {
+		return x.RemainingQuantity
+	}
+	return ""
+}
+
+type AmendOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MatchId       string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Price         string                 `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity      string                 `protobuf:"bytes,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AmendOrderRequest) Reset() {
+	*x = AmendOrderRequest{}
+	mi := &file_proto_orderbook_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AmendOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AmendOrderRequest) ProtoMessage() {}
+
+func (x *AmendOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AmendOrderRequest.ProtoReflect.Descriptor instead.
+func (*AmendOrderRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *AmendOrderRequest) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *AmendOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *AmendOrderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AmendOrderRequest) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *AmendOrderRequest) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+type AmendOrderResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	OrderId           string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Price             string                 `protobuf:"bytes,2,opt,name=price,proto3" json:"price,omitempty"`
+	RemainingQuantity string                 `protobuf:"bytes,3,opt,name=remaining_quantity,json=remainingQuantity,proto3" json:"remaining_quantity,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *AmendOrderResponse) Reset() {
+	*x = AmendOrderResponse{}
+	mi := &file_proto_orderbook_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AmendOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AmendOrderResponse) ProtoMessage() {}
+
+func (x *AmendOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AmendOrderResponse.ProtoReflect.Descriptor instead.
+func (*AmendOrderResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AmendOrderResponse) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *AmendOrderResponse) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *AmendOrderResponse) GetRemainingQuantity() string {
+	if x != nil {
+		return x.RemainingQuantity
+	}
+	return ""
+}
+
+type SelfTradePolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MatchId       string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	Policy        string                 `protobuf:"bytes,2,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SelfTradePolicyRequest) Reset() {
+	*x = SelfTradePolicyRequest{}
+	mi := &file_proto_orderbook_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelfTradePolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelfTradePolicyRequest) ProtoMessage() {}
+
+func (x *SelfTradePolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelfTradePolicyRequest.ProtoReflect.Descriptor instead.
+func (*SelfTradePolicyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SelfTradePolicyRequest) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *SelfTradePolicyRequest) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+type SelfTradePolicyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MatchId       string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	Policy        string                 `protobuf:"bytes,2,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SelfTradePolicyResponse) Reset() {
+	*x = SelfTradePolicyResponse{}
+	mi := &file_proto_orderbook_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelfTradePolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelfTradePolicyResponse) ProtoMessage() {}
+
+func (x *SelfTradePolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelfTradePolicyResponse.ProtoReflect.Descriptor instead.
+func (*SelfTradePolicyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SelfTradePolicyResponse) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *SelfTradePolicyResponse) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+type SurplusPolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MatchId       string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	Policy        string                 `protobuf:"bytes,2,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SurplusPolicyRequest) Reset() {
+	*x = SurplusPolicyRequest{}
+	mi := &file_proto_orderbook_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SurplusPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SurplusPolicyRequest) ProtoMessage() {}
+
+func (x *SurplusPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SurplusPolicyRequest.ProtoReflect.Descriptor instead.
+func (*SurplusPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SurplusPolicyRequest) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *SurplusPolicyRequest) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+type SurplusPolicyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MatchId       string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	Policy        string                 `protobuf:"bytes,2,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SurplusPolicyResponse) Reset() {
+	*x = SurplusPolicyResponse{}
+	mi := &file_proto_orderbook_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SurplusPolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SurplusPolicyResponse) ProtoMessage() {}
+
+func (x *SurplusPolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SurplusPolicyResponse.ProtoReflect.Descriptor instead.
+func (*SurplusPolicyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SurplusPolicyResponse) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *SurplusPolicyResponse) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+type MaxExposureRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MatchId       string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	MaxExposure   string                 `protobuf:"bytes,2,opt,name=max_exposure,json=maxExposure,proto3" json:"max_exposure,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MaxExposureRequest) Reset() {
+	*x = MaxExposureRequest{}
+	mi := &file_proto_orderbook_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MaxExposureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaxExposureRequest) ProtoMessage() {}
+
+func (x *MaxExposureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaxExposureRequest.ProtoReflect.Descriptor instead.
+func (*MaxExposureRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *MaxExposureRequest) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *MaxExposureRequest) GetMaxExposure() string {
+	if x != nil {
+		return x.MaxExposure
+	}
+	return ""
+}
+
+type MaxExposureResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MatchId       string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	MaxExposure   string                 `protobuf:"bytes,2,opt,name=max_exposure,json=maxExposure,proto3" json:"max_exposure,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MaxExposureResponse) Reset() {
+	*x = MaxExposureResponse{}
+	mi := &file_proto_orderbook_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MaxExposureResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaxExposureResponse) ProtoMessage() {}
+
+func (x *MaxExposureResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaxExposureResponse.ProtoReflect.Descriptor instead.
+func (*MaxExposureResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *MaxExposureResponse) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *MaxExposureResponse) GetMaxExposure() string {
+	if x != nil {
+		return x.MaxExposure
+	}
+	return ""
+}
+
+type WalletTransferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Amount        string                 `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WalletTransferRequest) Reset() {
+	*x = WalletTransferRequest{}
+	mi := &file_proto_orderbook_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalletTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalletTransferRequest) ProtoMessage() {}
+
+func (x *WalletTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalletTransferRequest.ProtoReflect.Descriptor instead.
+func (*WalletTransferRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *WalletTransferRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *WalletTransferRequest) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+type WalletRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WalletRequest) Reset() {
+	*x = WalletRequest{}
+	mi := &file_proto_orderbook_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalletRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalletRequest) ProtoMessage() {}
+
+func (x *WalletRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalletRequest.ProtoReflect.Descriptor instead.
+func (*WalletRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *WalletRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type WalletResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Available     string                 `protobuf:"bytes,2,opt,name=available,proto3" json:"available,omitempty"`
+	Reserved      string                 `protobuf:"bytes,3,opt,name=reserved,proto3" json:"reserved,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WalletResponse) Reset() {
+	*x = WalletResponse{}
+	mi := &file_proto_orderbook_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalletResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalletResponse) ProtoMessage() {}
+
+func (x *WalletResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalletResponse.ProtoReflect.Descriptor instead.
+func (*WalletResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *WalletResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *WalletResponse) GetAvailable() string {
+	if x != nil {
+		return x.Available
+	}
+	return ""
+}
+
+func (x *WalletResponse) GetReserved() string {
+	if x != nil {
+		return x.Reserved
+	}
+	return ""
+}
+
+type PositionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MatchId       string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PositionRequest) Reset() {
+	*x = PositionRequest{}
+	mi := &file_proto_orderbook_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PositionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PositionRequest) ProtoMessage() {}
+
+func (x *PositionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PositionRequest.ProtoReflect.Descriptor instead.
+func (*PositionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PositionRequest) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *PositionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type RunnerPosition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TeamId        string                 `protobuf:"bytes,1,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
+	Backed        string                 `protobuf:"bytes,2,opt,name=backed,proto3" json:"backed,omitempty"`
+	Laid          string                 `protobuf:"bytes,3,opt,name=laid,proto3" json:"laid,omitempty"`
+	IfWins        string                 `protobuf:"bytes,4,opt,name=if_wins,json=ifWins,proto3" json:"if_wins,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunnerPosition) Reset() {
+	*x = RunnerPosition{}
+	mi := &file_proto_orderbook_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunnerPosition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunnerPosition) ProtoMessage() {}
+
+func (x *RunnerPosition) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunnerPosition.ProtoReflect.Descriptor instead.
+func (*RunnerPosition) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *RunnerPosition) GetTeamId() string {
+	if x != nil {
+		return x.TeamId
+	}
+	return ""
+}
+
+func (x *RunnerPosition) GetBacked() string {
+	if x != nil {
+		return x.Backed
+	}
+	return ""
+}
+
+func (x *RunnerPosition) GetLaid() string {
+	if x != nil {
+		return x.Laid
+	}
+	return ""
+}
+
+func (x *RunnerPosition) GetIfWins() string {
+	if x != nil {
+		return x.IfWins
+	}
+	return ""
+}
+
+type PositionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MatchId       string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Staked        string                 `protobuf:"bytes,3,opt,name=staked,proto3" json:"staked,omitempty"`
+	Runners       []*RunnerPosition      `protobuf:"bytes,4,rep,name=runners,proto3" json:"runners,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PositionResponse) Reset() {
+	*x = PositionResponse{}
+	mi := &file_proto_orderbook_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PositionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PositionResponse) ProtoMessage() {}
+
+func (x *PositionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PositionResponse.ProtoReflect.Descriptor instead.
+func (*PositionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *PositionResponse) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *PositionResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PositionResponse) GetStaked() string {
+	if x != nil {
+		return x.Staked
+	}
+	return ""
+}
+
+func (x *PositionResponse) GetRunners() []*RunnerPosition {
+	if x != nil {
+		return x.Runners
+	}
+	return nil
+}
+
+type CashOutRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	MatchId        string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	UserId         string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ToleranceTicks int32                  `protobuf:"varint,3,opt,name=tolerance_ticks,json=toleranceTicks,proto3" json:"tolerance_ticks,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CashOutRequest) Reset() {
+	*x = CashOutRequest{}
+	mi := &file_proto_orderbook_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CashOutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CashOutRequest) ProtoMessage() {}
+
+func (x *CashOutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CashOutRequest.ProtoReflect.Descriptor instead.
+func (*CashOutRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *CashOutRequest) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *CashOutRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CashOutRequest) GetToleranceTicks() int32 {
+	if x != nil {
+		return x.ToleranceTicks
+	}
+	return 0
+}
+
+type CashOutQuote struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MatchId       string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TeamId        string                 `protobuf:"bytes,3,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
+	Side          string                 `protobuf:"bytes,4,opt,name=side,proto3" json:"side,omitempty"`
+	Price         string                 `protobuf:"bytes,5,opt,name=price,proto3" json:"price,omitempty"`
+	Stake         string                 `protobuf:"bytes,6,opt,name=stake,proto3" json:"stake,omitempty"`
+	Available     string                 `protobuf:"bytes,7,opt,name=available,proto3" json:"available,omitempty"`
+	Profit        string                 `protobuf:"bytes,8,opt,name=profit,proto3" json:"profit,omitempty"`
+	After         *PositionResponse      `protobuf:"bytes,9,opt,name=after,proto3" json:"after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CashOutQuote) Reset() {
+	*x = CashOutQuote{}
+	mi := &file_proto_orderbook_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CashOutQuote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CashOutQuote) ProtoMessage() {}
+
+func (x *CashOutQuote) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CashOutQuote.ProtoReflect.Descriptor instead.
+func (*CashOutQuote) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *CashOutQuote) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *CashOutQuote) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CashOutQuote) GetTeamId() string {
+	if x != nil {
+		return x.TeamId
+	}
+	return ""
+}
+
+func (x *CashOutQuote) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *CashOutQuote) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *CashOutQuote) GetStake() string {
+	if x != nil {
+		return x.Stake
+	}
+	return ""
+}
+
+func (x *CashOutQuote) GetAvailable() string {
+	if x != nil {
+		return x.Available
+	}
+	return ""
+}
+
+func (x *CashOutQuote) GetProfit() string {
+	if x != nil {
+		return x.Profit
+	}
+	return ""
+}
+
+func (x *CashOutQuote) GetAfter() *PositionResponse {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+type CashOutResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Quote         *CashOutQuote          `protobuf:"bytes,1,opt,name=quote,proto3" json:"quote,omitempty"`
+	Report        *ExecutionReport       `protobuf:"bytes,2,opt,name=report,proto3" json:"report,omitempty"`
+	Position      *PositionResponse      `protobuf:"bytes,3,opt,name=position,proto3" json:"position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CashOutResponse) Reset() {
+	*x = CashOutResponse{}
+	mi := &file_proto_orderbook_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CashOutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CashOutResponse) ProtoMessage() {}
+
+func (x *CashOutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CashOutResponse.ProtoReflect.Descriptor instead.
+func (*CashOutResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *CashOutResponse) GetQuote() *CashOutQuote {
+	if x != nil {
+		return x.Quote
+	}
+	return nil
+}
+
+func (x *CashOutResponse) GetReport() *ExecutionReport {
+	if x != nil {
+		return x.Report
+	}
+	return nil
+}
+
+func (x *CashOutResponse) GetPosition() *PositionResponse {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+type MarketDepthRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MatchId       string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	TeamId        string                 `protobuf:"bytes,2,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
+	Levels        int32                  `protobuf:"varint,3,opt,name=levels,proto3" json:"levels,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarketDepthRequest) Reset() {
+	*x = MarketDepthRequest{}
+	mi := &file_proto_orderbook_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarketDepthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarketDepthRequest) ProtoMessage() {}
+
+func (x *MarketDepthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarketDepthRequest.ProtoReflect.Descriptor instead.
+func (*MarketDepthRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *MarketDepthRequest) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *MarketDepthRequest) GetTeamId() string {
+	if x != nil {
+		return x.TeamId
+	}
+	return ""
+}
+
+func (x *MarketDepthRequest) GetLevels() int32 {
+	if x != nil {
+		return x.Levels
+	}
+	return 0
+}
+
+type DepthLevel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Price         string                 `protobuf:"bytes,1,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity      string                 `protobuf:"bytes,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Orders        int32                  `protobuf:"varint,3,opt,name=orders,proto3" json:"orders,omitempty"`
+	Virtual       string                 `protobuf:"bytes,4,opt,name=virtual,proto3" json:"virtual,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DepthLevel) Reset() {
+	*x = DepthLevel{}
+	mi := &file_proto_orderbook_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DepthLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepthLevel) ProtoMessage() {}
+
+func (x *DepthLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepthLevel.ProtoReflect.Descriptor instead.
+func (*DepthLevel) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *DepthLevel) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *DepthLevel) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+func (x *DepthLevel) GetOrders() int32 {
+	if x != nil {
+		return x.Orders
+	}
+	return 0
+}
+
+func (x *DepthLevel) GetVirtual() string {
+	if x != nil {
+		return x.Virtual
+	}
+	return ""
+}
+
+type MarketDepth struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MatchId       string                 `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	TeamId        string                 `protobuf:"bytes,2,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
+	Bids          []*DepthLevel          `protobuf:"bytes,3,rep,name=bids,proto3" json:"bids,omitempty"`
+	Asks          []*DepthLevel          `protobuf:"bytes,4,rep,name=asks,proto3" json:"asks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarketDepth) Reset() {
+	*x = MarketDepth{}
+	mi := &file_proto_orderbook_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarketDepth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarketDepth) ProtoMessage() {}
+
+func (x *MarketDepth) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderbook_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarketDepth.ProtoReflect.Descriptor instead.
+func (*MarketDepth) Descriptor() ([]byte, []int) {
+	return file_proto_orderbook_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *MarketDepth) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+func (x *MarketDepth) GetTeamId() string {
+	if x != nil {
+		return x.TeamId
+	}
+	return ""
+}
+
+func (x *MarketDepth) GetBids() []*DepthLevel {
+	if x != nil {
+		return x.Bids
+	}
+	return nil
+}
+
+func (x *MarketDepth) GetAsks() []*DepthLevel {
+	if x != nil {
+		return x.Asks
+	}
+	return nil
+}
+
+var File_proto_orderbook_proto protoreflect.FileDescriptor
+
+const file_proto_orderbook_proto_rawDesc = "" +
+	"\n" +
+	"\x15proto/orderbook.proto\x12\torderbook\"\x86\x02\n" +
+	"\fMatchRequest\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x12\x15\n" +
+	"\x06team_a\x18\x02 \x01(\tR\x05teamA\x12\x15\n" +
+	"\x06team_b\x18\x03 \x01(\tR\x05teamB\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x04 \x01(\x03R\tstartTime\x12*\n" +
+	"\x11self_trade_policy\x18\x05 \x01(\tR\x0fselfTradePolicy\x12%\n" +
+	"\x0esurplus_policy\x18\x06 \x01(\tR\rsurplusPolicy\x12\x18\n" +
+	"\arunners\x18\a \x03(\tR\arunners\x12!\n" +
+	"\fmax_exposure\x18\b \x01(\tR\vmaxExposure\"/\n" +
+	"\x15RegisterMatchResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\"b\n" +
+	"\x17TransitionMarketRequest\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"/\n" +
+	"\x12MarketStateRequest\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\"m\n" +
+	"\x13MarketStateResponse\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12%\n" +
+	"\x0eprevious_state\x18\x03 \x01(\tR\rpreviousState\"W\n" +
+	"\x12SettleMatchRequest\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x12&\n" +
+	"\x0fwinning_team_id\x18\x02 \x01(\tR\rwinningTeamId\"E\n" +
+	"\x10VoidMatchRequest\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"y\n" +
+	"\x0eUserSettlement\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06staked\x18\x02 \x01(\tR\x06staked\x12\x10\n" +
+	"\x03won\x18\x03 \x01(\tR\x03won\x12\x12\n" +
+	"\x04lost\x18\x04 \x01(\tR\x04lost\x12\x10\n" +
+	"\x03net\x18\x05 \x01(\tR\x03net\"\xda\x01\n" +
+	"\x10SettlementReport\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x12&\n" +
+	"\x0fwinning_team_id\x18\x02 \x01(\tR\rwinningTeamId\x12/\n" +
+	"\x05users\x18\x03 \x03(\v2\x19.orderbook.UserSettlementR\x05users\x12\x1b\n" +
+	"\thouse_net\x18\x04 \x01(\tR\bhouseNet\x12\x1d\n" +
+	"\n" +
+	"settled_at\x18\x05 \x01(\x03R\tsettledAt\x12\x16\n" +
+	"\x06voided\x18\x06 \x01(\bR\x06voided\"c\n" +
+	"\x12CancelOrderRequest\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\"\xb9\x02\n" +
+	"\x11PlaceOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x19\n" +
+	"\bmatch_id\x18\x02 \x01(\tR\amatchId\x12\x17\n" +
+	"\ateam_id\x18\x03 \x01(\tR\x06teamId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04side\x18\x05 \x01(\tR\x04side\x12\x14\n" +
+	"\x05price\x18\x06 \x01(\tR\x05price\x12\x1a\n" +
+	"\bquantity\x18\a \x01(\tR\bquantity\x12\"\n" +
+	"\rtime_in_force\x18\b \x01(\tR\vtimeInForce\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\t \x01(\x03R\texpiresAt\x12\x12\n" +
+	"\x04type\x18\n" +
+	" \x01(\tR\x04type\x12\x1f\n" +
+	"\vworst_price\x18\v \x01(\tR\n" +
+	"worstPrice\"\xdf\x01\n" +
+	"\x04Fill\x122\n" +
+	"\x15counterparty_order_id\x18\x01 \x01(\tR\x13counterpartyOrderId\x12\x14\n" +
+	"\x05price\x18\x02 \x01(\tR\x05price\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\tR\bquantity\x12\x1d\n" +
+	"\n" +
+	"match_type\x18\x04 \x01(\tR\tmatchType\x12\x1c\n" +
+	"\tliability\x18\x05 \x01(\tR\tliability\x124\n" +
+	"\x16counterparty_order_ids\x18\x06 \x03(\tR\x14counterpartyOrderIds\"\x81\x02\n" +
+	"\x0fExecutionReport\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12%\n" +
+	"\x05fills\x18\x03 \x03(\v2\x0f.orderbook.FillR\x05fills\x12)\n" +
+	"\x10resting_quantity\x18\x04 \x01(\tR\x0frestingQuantity\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12\x1f\n" +
+	"\vreject_code\x18\x06 \x01(\tR\n" +
+	"rejectCode\x120\n" +
+	"\x14self_trade_prevented\x18\a \x01(\tR\x12selfTradePrevented\"_\n" +
+	"\x13CancelOrderResponse\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12-\n" +
+	"\x12remaining_quantity\x18\x02 \x01(\tR\x11remainingQuantity\"\x94\x01\n" +
+	"\x11AmendOrderRequest\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\tR\x05price\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\tR\bquantity\"t\n" +
+	"\x12AmendOrderResponse\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x14\n" +
+	"\x05price\x18\x02 \x01(\tR\x05price\x12-\n" +
+	"\x12remaining_quantity\x18\x03 \x01(\tR\x11remainingQuantity\"K\n" +
+	"\x16SelfTradePolicyRequest\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x12\x16\n" +
+	"\x06policy\x18\x02 \x01(\tR\x06policy\"L\n" +
+	"\x17SelfTradePolicyResponse\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x12\x16\n" +
+	"\x06policy\x18\x02 \x01(\tR\x06policy\"I\n" +
+	"\x14SurplusPolicyRequest\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x12\x16\n" +
+	"\x06policy\x18\x02 \x01(\tR\x06policy\"J\n" +
+	"\x15SurplusPolicyResponse\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x12\x16\n" +
+	"\x06policy\x18\x02 \x01(\tR\x06policy\"R\n" +
+	"\x12MaxExposureRequest\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x12!\n" +
+	"\fmax_exposure\x18\x02 \x01(\tR\vmaxExposure\"S\n" +
+	"\x13MaxExposureResponse\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x12!\n" +
+	"\fmax_exposure\x18\x02 \x01(\tR\vmaxExposure\"H\n" +
+	"\x15WalletTransferRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\tR\x06amount\"(\n" +
+	"\rWalletRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"c\n" +
+	"\x0eWalletResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1c\n" +
+	"\tavailable\x18\x02 \x01(\tR\tavailable\x12\x1a\n" +
+	"\breserved\x18\x03 \x01(\tR\breserved\"E\n" +
+	"\x0fPositionRequest\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"n\n" +
+	"\x0eRunnerPosition\x12\x17\n" +
+	"\ateam_id\x18\x01 \x01(\tR\x06teamId\x12\x16\n" +
+	"\x06backed\x18\x02 \x01(\tR\x06backed\x12\x12\n" +
+	"\x04laid\x18\x03 \x01(\tR\x04laid\x12\x17\n" +
+	"\aif_wins\x18\x04 \x01(\tR\x06ifWins\"\x93\x01\n" +
+	"\x10PositionResponse\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06staked\x18\x03 \x01(\tR\x06staked\x123\n" +
+	"\arunners\x18\x04 \x03(\v2\x19.orderbook.RunnerPositionR\arunners\"m\n" +
+	"\x0eCashOutRequest\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12'\n" +
+	"\x0ftolerance_ticks\x18\x03 \x01(\x05R\x0etoleranceTicks\"\x84\x02\n" +
+	"\fCashOutQuote\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x17\n" +
+	"\ateam_id\x18\x03 \x01(\tR\x06teamId\x12\x12\n" +
+	"\x04side\x18\x04 \x01(\tR\x04side\x12\x14\n" +
+	"\x05price\x18\x05 \x01(\tR\x05price\x12\x14\n" +
+	"\x05stake\x18\x06 \x01(\tR\x05stake\x12\x1c\n" +
+	"\tavailable\x18\a \x01(\tR\tavailable\x12\x16\n" +
+	"\x06profit\x18\b \x01(\tR\x06profit\x121\n" +
+	"\x05after\x18\t \x01(\v2\x1b.orderbook.PositionResponseR\x05after\"\xad\x01\n" +
+	"\x0fCashOutResponse\x12-\n" +
+	"\x05quote\x18\x01 \x01(\v2\x17.orderbook.CashOutQuoteR\x05quote\x122\n" +
+	"\x06report\x18\x02 \x01(\v2\x1a.orderbook.ExecutionReportR\x06report\x127\n" +
+	"\bposition\x18\x03 \x01(\v2\x1b.orderbook.PositionResponseR\bposition\"`\n" +
+	"\x12MarketDepthRequest\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x12\x17\n" +
+	"\ateam_id\x18\x02 \x01(\tR\x06teamId\x12\x16\n" +
+	"\x06levels\x18\x03 \x01(\x05R\x06levels\"p\n" +
+	"\n" +
+	"DepthLevel\x12\x14\n" +
+	"\x05price\x18\x01 \x01(\tR\x05price\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\tR\bquantity\x12\x16\n" +
+	"\x06orders\x18\x03 \x01(\x05R\x06orders\x12\x18\n" +
+	"\avirtual\x18\x04 \x01(\tR\avirtual\"\x97\x01\n" +
+	"\vMarketDepth\x12\x19\n" +
+	"\bmatch_id\x18\x01 \x01(\tR\amatchId\x12\x17\n" +
+	"\ateam_id\x18\x02 \x01(\tR\x06teamId\x12)\n" +
+	"\x04bids\x18\x03 \x03(\v2\x15.orderbook.DepthLevelR\x04bids\x12)\n" +
+	"\x04asks\x18\x04 \x03(\v2\x15.orderbook.DepthLevelR\x04asks2\xb9\v\n" +
+	"\x10OrderbookService\x12J\n" +
+	"\rRegisterMatch\x12\x17.orderbook.MatchRequest\x1a .orderbook.RegisterMatchResponse\x12V\n" +
+	"\x10TransitionMarket\x12\".orderbook.TransitionMarketRequest\x1a\x1e.orderbook.MarketStateResponse\x12O\n" +
+	"\x0eGetMarketState\x12\x1d.orderbook.MarketStateRequest\x1a\x1e.orderbook.MarketStateResponse\x12I\n" +
+	"\vSettleMatch\x12\x1d.orderbook.SettleMatchRequest\x1a\x1b.orderbook.SettlementReport\x12K\n" +
+	"\rResettleMatch\x12\x1d.orderbook.SettleMatchRequest\x1a\x1b.orderbook.SettlementReport\x12E\n" +
+	"\tVoidMatch\x12\x1b.orderbook.VoidMatchRequest\x1a\x1b.orderbook.SettlementReport\x12F\n" +
+	"\n" +
+	"PlaceOrder\x12\x1c.orderbook.PlaceOrderRequest\x1a\x1a.orderbook.ExecutionReport\x12L\n" +
+	"\vCancelOrder\x12\x1d.orderbook.CancelOrderRequest\x1a\x1e.orderbook.CancelOrderResponse\x12I\n" +
+	"\n" +
+	"AmendOrder\x12\x1c.orderbook.AmendOrderRequest\x1a\x1d.orderbook.AmendOrderResponse\x12[\n" +
+	"\x12SetSelfTradePolicy\x12!.orderbook.SelfTradePolicyRequest\x1a\".orderbook.SelfTradePolicyResponse\x12U\n" +
+	"\x10SetSurplusPolicy\x12\x1f.orderbook.SurplusPolicyRequest\x1a .orderbook.SurplusPolicyResponse\x12O\n" +
+	"\x0eSetMaxExposure\x12\x1d.orderbook.MaxExposureRequest\x1a\x1e.orderbook.MaxExposureResponse\x12F\n" +
+	"\aDeposit\x12 .orderbook.WalletTransferRequest\x1a\x19.orderbook.WalletResponse\x12G\n" +
+	"\bWithdraw\x12 .orderbook.WalletTransferRequest\x1a\x19.orderbook.WalletResponse\x12@\n" +
+	"\tGetWallet\x12\x18.orderbook.WalletRequest\x1a\x19.orderbook.WalletResponse\x12F\n" +
+	"\vGetPosition\x12\x1a.orderbook.PositionRequest\x1a\x1b.orderbook.PositionResponse\x12G\n" +
+	"\x0eGetMarketDepth\x12\x1d.orderbook.MarketDepthRequest\x1a\x16.orderbook.MarketDepth\x12E\n" +
+	"\x0fGetCashOutQuote\x12\x19.orderbook.CashOutRequest\x1a\x17.orderbook.CashOutQuote\x12@\n" +
+	"\aCashOut\x12\x19.orderbook.CashOutRequest\x1a\x1a.orderbook.CashOutResponseB-Z+github.com/amithshubhan/Bet_Now/orderbookpbb\x06proto3"
 
 var (
 	file_proto_orderbook_proto_rawDescOnce sync.Once
@@ -151,19 +2425,96 @@ func file_proto_orderbook_proto_rawDescGZIP() []byte {
 	return file_proto_orderbook_proto_rawDescData
 }
 
-var file_proto_orderbook_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_proto_orderbook_proto_msgTypes = make([]protoimpl.MessageInfo, 34)
 var file_proto_orderbook_proto_goTypes = []any{
-	(*MatchRequest)(nil),          // 0: orderbook.MatchRequest
-	(*RegisterMatchResponse)(nil), // 1: orderbook.RegisterMatchResponse
+	(*MatchRequest)(nil),            // 0: orderbook.MatchRequest
+	(*RegisterMatchResponse)(nil),   // 1: orderbook.RegisterMatchResponse
+	(*TransitionMarketRequest)(nil), // 2: orderbook.TransitionMarketRequest
+	(*MarketStateRequest)(nil),      // 3: orderbook.MarketStateRequest
+	(*MarketStateResponse)(nil),     // 4: orderbook.MarketStateResponse
+	(*SettleMatchRequest)(nil),      // 5: orderbook.SettleMatchRequest
+	(*VoidMatchRequest)(nil),        // 6: orderbook.VoidMatchRequest
+	(*UserSettlement)(nil),          // 7: orderbook.UserSettlement
+	(*SettlementReport)(nil),        // 8: orderbook.SettlementReport
+	(*CancelOrderRequest)(nil),      // 9: orderbook.CancelOrderRequest
+	(*PlaceOrderRequest)(nil),       // 10: orderbook.PlaceOrderRequest
+	(*Fill)(nil),                    // 11: orderbook.Fill
+	(*ExecutionReport)(nil),         // 12: orderbook.ExecutionReport
+	(*CancelOrderResponse)(nil),     // 13: orderbook.CancelOrderResponse
+	(*AmendOrderRequest)(nil),       // 14: orderbook.AmendOrderRequest
+	(*AmendOrderResponse)(nil),      // 15: orderbook.AmendOrderResponse
+	(*SelfTradePolicyRequest)(nil),  // 16: orderbook.SelfTradePolicyRequest
+	(*SelfTradePolicyResponse)(nil), // 17: orderbook.SelfTradePolicyResponse
+	(*SurplusPolicyRequest)(nil),    // 18: orderbook.SurplusPolicyRequest
+	(*SurplusPolicyResponse)(nil),   // 19: orderbook.SurplusPolicyResponse
+	(*MaxExposureRequest)(nil),      // 20: orderbook.MaxExposureRequest
+	(*MaxExposureResponse)(nil),     // 21: orderbook.MaxExposureResponse
+	(*WalletTransferRequest)(nil),   // 22: orderbook.WalletTransferRequest
+	(*WalletRequest)(nil),           // 23: orderbook.WalletRequest
+	(*WalletResponse)(nil),          // 24: orderbook.WalletResponse
+	(*PositionRequest)(nil),         // 25: orderbook.PositionRequest
+	(*RunnerPosition)(nil),          // 26: orderbook.RunnerPosition
+	(*PositionResponse)(nil),        // 27: orderbook.PositionResponse
+	(*CashOutRequest)(nil),          // 28: orderbook.CashOutRequest
+	(*CashOutQuote)(nil),            // 29: orderbook.CashOutQuote
+	(*CashOutResponse)(nil),         // 30: orderbook.CashOutResponse
+	(*MarketDepthRequest)(nil),      // 31: orderbook.MarketDepthRequest
+	(*DepthLevel)(nil),              // 32: orderbook.DepthLevel
+	(*MarketDepth)(nil),             // 33: orderbook.MarketDepth
 }
 var file_proto_orderbook_proto_depIdxs = []int32{
-	0, // 0: orderbook.OrderbookService.RegisterMatch:input_type -> orderbook.MatchRequest
-	1, // 1: orderbook.OrderbookService.RegisterMatch:output_type -> orderbook.RegisterMatchResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	7,  // 0: orderbook.SettlementReport.users:type_name -> orderbook.UserSettlement
+	11, // 1: orderbook.ExecutionReport.fills:type_name -> orderbook.Fill
+	26, // 2: orderbook.PositionResponse.runners:type_name -> orderbook.RunnerPosition
+	27, // 3: orderbook.CashOutQuote.after:type_name -> orderbook.PositionResponse
+	29, // 4: orderbook.CashOutResponse.quote:type_name -> orderbook.CashOutQuote
+	12, // 5: orderbook.CashOutResponse.report:type_name -> orderbook.ExecutionReport
+	27, // 6: orderbook.CashOutResponse.position:type_name -> orderbook.PositionResponse
+	32, // 7: orderbook.MarketDepth.bids:type_name -> orderbook.DepthLevel
+	32, // 8: orderbook.MarketDepth.asks:type_name -> orderbook.DepthLevel
+	0,  // 9: orderbook.OrderbookService.RegisterMatch:input_type -> orderbook.MatchRequest
+	2,  // 10: orderbook.OrderbookService.TransitionMarket:input_type -> orderbook.TransitionMarketRequest
+	3,  // 11: orderbook.OrderbookService.GetMarketState:input_type -> orderbook.MarketStateRequest
+	5,  // 12: orderbook.OrderbookService.SettleMatch:input_type -> orderbook.SettleMatchRequest
+	5,  // 13: orderbook.OrderbookService.ResettleMatch:input_type -> orderbook.SettleMatchRequest
+	6,  // 14: orderbook.OrderbookService.VoidMatch:input_type -> orderbook.VoidMatchRequest
+	10, // 15: orderbook.OrderbookService.PlaceOrder:input_type -> orderbook.PlaceOrderRequest
+	9,  // 16: orderbook.OrderbookService.CancelOrder:input_type -> orderbook.CancelOrderRequest
+	14, // 17: orderbook.OrderbookService.AmendOrder:input_type -> orderbook.AmendOrderRequest
+	16, // 18: orderbook.OrderbookService.SetSelfTradePolicy:input_type -> orderbook.SelfTradePolicyRequest
+	18, // 19: orderbook.OrderbookService.SetSurplusPolicy:input_type -> orderbook.SurplusPolicyRequest
+	20, // 20: orderbook.OrderbookService.SetMaxExposure:input_type -> orderbook.MaxExposureRequest
+	22, // 21: orderbook.OrderbookService.Deposit:input_type -> orderbook.WalletTransferRequest
+	22, // 22: orderbook.OrderbookService.Withdraw:input_type -> orderbook.WalletTransferRequest
+	23, // 23: orderbook.OrderbookService.GetWallet:input_type -> orderbook.WalletRequest
+	25, // 24: orderbook.OrderbookService.GetPosition:input_type -> orderbook.PositionRequest
+	31, // 25: orderbook.OrderbookService.GetMarketDepth:input_type -> orderbook.MarketDepthRequest
+	28, // 26: orderbook.OrderbookService.GetCashOutQuote:input_type -> orderbook.CashOutRequest
+	28, // 27: orderbook.OrderbookService.CashOut:input_type -> orderbook.CashOutRequest
+	1,  // 28: orderbook.OrderbookService.RegisterMatch:output_type -> orderbook.RegisterMatchResponse
+	4,  // 29: orderbook.OrderbookService.TransitionMarket:output_type -> orderbook.MarketStateResponse
+	4,  // 30: orderbook.OrderbookService.GetMarketState:output_type -> orderbook.MarketStateResponse
+	8,  // 31: orderbook.OrderbookService.SettleMatch:output_type -> orderbook.SettlementReport
+	8,  // 32: orderbook.OrderbookService.ResettleMatch:output_type -> orderbook.SettlementReport
+	8,  // 33: orderbook.OrderbookService.VoidMatch:output_type -> orderbook.SettlementReport
+	12, // 34: orderbook.OrderbookService.PlaceOrder:output_type -> orderbook.ExecutionReport
+	13, // 35: orderbook.OrderbookService.CancelOrder:output_type -> orderbook.CancelOrderResponse
+	15, // 36: orderbook.OrderbookService.AmendOrder:output_type -> orderbook.AmendOrderResponse
+	17, // 37: orderbook.OrderbookService.SetSelfTradePolicy:output_type -> orderbook.SelfTradePolicyResponse
+	19, // 38: orderbook.OrderbookService.SetSurplusPolicy:output_type -> orderbook.SurplusPolicyResponse
+	21, // 39: orderbook.OrderbookService.SetMaxExposure:output_type -> orderbook.MaxExposureResponse
+	24, // 40: orderbook.OrderbookService.Deposit:output_type -> orderbook.WalletResponse
+	24, // 41: orderbook.OrderbookService.Withdraw:output_type -> orderbook.WalletResponse
+	24, // 42: orderbook.OrderbookService.GetWallet:output_type -> orderbook.WalletResponse
+	27, // 43: orderbook.OrderbookService.GetPosition:output_type -> orderbook.PositionResponse
+	33, // 44: orderbook.OrderbookService.GetMarketDepth:output_type -> orderbook.MarketDepth
+	29, // 45: orderbook.OrderbookService.GetCashOutQuote:output_type -> orderbook.CashOutQuote
+	30, // 46: orderbook.OrderbookService.CashOut:output_type -> orderbook.CashOutResponse
+	28, // [28:47] is the sub-list for method output_type
+	9,  // [9:28] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_proto_orderbook_proto_init() }
@@ -177,7 +2528,7 @@ func file_proto_orderbook_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_orderbook_proto_rawDesc), len(file_proto_orderbook_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   34,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,7 +19,25 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	OrderbookService_RegisterMatch_FullMethodName = "/orderbook.OrderbookService/RegisterMatch"
+	OrderbookService_RegisterMatch_FullMethodName      = "/orderbook.OrderbookService/RegisterMatch"
+	OrderbookService_TransitionMarket_FullMethodName   = "/orderbook.OrderbookService/TransitionMarket"
+	OrderbookService_GetMarketState_FullMethodName     = "/orderbook.OrderbookService/GetMarketState"
+	OrderbookService_SettleMatch_FullMethodName        = "/orderbook.OrderbookService/SettleMatch"
+	OrderbookService_ResettleMatch_FullMethodName      = "/orderbook.OrderbookService/ResettleMatch"
+	OrderbookService_VoidMatch_FullMethodName          = "/orderbook.OrderbookService/VoidMatch"
+	OrderbookService_PlaceOrder_FullMethodName         = "/orderbook.OrderbookService/PlaceOrder"
+	OrderbookService_CancelOrder_FullMethodName        = "/orderbook.OrderbookService/CancelOrder"
+	OrderbookService_AmendOrder_FullMethodName         = "/orderbook.OrderbookService/AmendOrder"
+	OrderbookService_SetSelfTradePolicy_FullMethodName = "/orderbook.OrderbookService/SetSelfTradePolicy"
+	OrderbookService_SetSurplusPolicy_FullMethodName   = "/orderbook.OrderbookService/SetSurplusPolicy"
+	OrderbookService_SetMaxExposure_FullMethodName     = "/orderbook.OrderbookService/SetMaxExposure"
+	OrderbookService_Deposit_FullMethodName            = "/orderbook.OrderbookService/Deposit"
+	OrderbookService_Withdraw_FullMethodName           = "/orderbook.OrderbookService/Withdraw"
+	OrderbookService_GetWallet_FullMethodName          = "/orderbook.OrderbookService/GetWallet"
+	OrderbookService_GetPosition_FullMethodName        = "/orderbook.OrderbookService/GetPosition"
+	OrderbookService_GetMarketDepth_FullMethodName     = "/orderbook.OrderbookService/GetMarketDepth"
+	OrderbookService_GetCashOutQuote_FullMethodName    = "/orderbook.OrderbookService/GetCashOutQuote"
+	OrderbookService_CashOut_FullMethodName            = "/orderbook.OrderbookService/CashOut"
 )
 
 // OrderbookServiceClient is the client API for OrderbookService service.
@@ -27,6 +45,24 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type OrderbookServiceClient interface {
 	RegisterMatch(ctx context.Context, in *MatchRequest, opts ...grpc.CallOption) (*RegisterMatchResponse, error)
+	TransitionMarket(ctx context.Context, in *TransitionMarketRequest, opts ...grpc.CallOption) (*MarketStateResponse, error)
+	GetMarketState(ctx context.Context, in *MarketStateRequest, opts ...grpc.CallOption) (*MarketStateResponse, error)
+	SettleMatch(ctx context.Context, in *SettleMatchRequest, opts ...grpc.CallOption) (*SettlementReport, error)
+	ResettleMatch(ctx context.Context, in *SettleMatchRequest, opts ...grpc.CallOption) (*SettlementReport, error)
+	VoidMatch(ctx context.Context, in *VoidMatchRequest, opts ...grpc.CallOption) (*SettlementReport, error)
+	PlaceOrder(ctx context.Context, in *PlaceOrderRequest, opts ...grpc.CallOption) (*ExecutionReport, error)
+	CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderResponse, error)
+	AmendOrder(ctx context.Context, in *AmendOrderRequest, opts ...grpc.CallOption) (*AmendOrderResponse, error)
+	SetSelfTradePolicy(ctx context.Context, in *SelfTradePolicyRequest, opts ...grpc.CallOption) (*SelfTradePolicyResponse, error)
+	SetSurplusPolicy(ctx context.Context, in *SurplusPolicyRequest, opts ...grpc.CallOption) (*SurplusPolicyResponse, error)
+	SetMaxExposure(ctx context.Context, in *MaxExposureRequest, opts ...grpc.CallOption) (*MaxExposureResponse, error)
+	Deposit(ctx context.Context, in *WalletTransferRequest, opts ...grpc.CallOption) (*WalletResponse, error)
+	Withdraw(ctx context.Context, in *WalletTransferRequest, opts ...grpc.CallOption) (*WalletResponse, error)
+	GetWallet(ctx context.Context, in *WalletRequest, opts ...grpc.CallOption) (*WalletResponse, error)
+	GetPosition(ctx context.Context, in *PositionRequest, opts ...grpc.CallOption) (*PositionResponse, error)
+	GetMarketDepth(ctx context.Context, in *MarketDepthRequest, opts ...grpc.CallOption) (*MarketDepth, error)
+	GetCashOutQuote(ctx context.Context, in *CashOutRequest, opts ...grpc.CallOption) (*CashOutQuote, error)
+	CashOut(ctx context.Context, in *CashOutRequest, opts ...grpc.CallOption) (*CashOutResponse, error)
 }
 
 type orderbookServiceClient struct {
@@ -47,11 +83,209 @@ func (c *orderbookServiceClient) RegisterMatch(ctx context.Context, in *MatchReq
 	return out, nil
 }
 
+func (c *orderbookServiceClient) TransitionMarket(ctx context.Context, in *TransitionMarketRequest, opts ...grpc.CallOption) (*MarketStateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MarketStateResponse)
+	err := c.cc.Invoke(ctx, OrderbookService_TransitionMarket_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderbookServiceClient) GetMarketState(ctx context.Context, in *MarketStateRequest, opts ...grpc.CallOption) (*MarketStateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MarketStateResponse)
+	err := c.cc.Invoke(ctx, OrderbookService_GetMarketState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderbookServiceClient) SettleMatch(ctx context.Context, in *SettleMatchRequest, opts ...grpc.CallOption) (*SettlementReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SettlementReport)
+	err := c.cc.Invoke(ctx, OrderbookService_SettleMatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderbookServiceClient) ResettleMatch(ctx context.Context, in *SettleMatchRequest, opts ...grpc.CallOption) (*SettlementReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SettlementReport)
+	err := c.cc.Invoke(ctx, OrderbookService_ResettleMatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderbookServiceClient) VoidMatch(ctx context.Context, in *VoidMatchRequest, opts ...grpc.CallOption) (*SettlementReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SettlementReport)
+	err := c.cc.Invoke(ctx, OrderbookService_VoidMatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderbookServiceClient) PlaceOrder(ctx context.Context, in *PlaceOrderRequest, opts ...grpc.CallOption) (*ExecutionReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExecutionReport)
+	err := c.cc.Invoke(ctx, OrderbookService_PlaceOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderbookServiceClient) CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelOrderResponse)
+	err := c.cc.Invoke(ctx, OrderbookService_CancelOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderbookServiceClient) AmendOrder(ctx context.Context, in *AmendOrderRequest, opts ...grpc.CallOption) (*AmendOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AmendOrderResponse)
+	err := c.cc.Invoke(ctx, OrderbookService_AmendOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderbookServiceClient) SetSelfTradePolicy(ctx context.Context, in *SelfTradePolicyRequest, opts ...grpc.CallOption) (*SelfTradePolicyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SelfTradePolicyResponse)
+	err := c.cc.Invoke(ctx, OrderbookService_SetSelfTradePolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderbookServiceClient) SetSurplusPolicy(ctx context.Context, in *SurplusPolicyRequest, opts ...grpc.CallOption) (*SurplusPolicyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SurplusPolicyResponse)
+	err := c.cc.Invoke(ctx, OrderbookService_SetSurplusPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderbookServiceClient) SetMaxExposure(ctx context.Context, in *MaxExposureRequest, opts ...grpc.CallOption) (*MaxExposureResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MaxExposureResponse)
+	err := c.cc.Invoke(ctx, OrderbookService_SetMaxExposure_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderbookServiceClient) Deposit(ctx context.Context, in *WalletTransferRequest, opts ...grpc.CallOption) (*WalletResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WalletResponse)
+	err := c.cc.Invoke(ctx, OrderbookService_Deposit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderbookServiceClient) Withdraw(ctx context.Context, in *WalletTransferRequest, opts ...grpc.CallOption) (*WalletResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WalletResponse)
+	err := c.cc.Invoke(ctx, OrderbookService_Withdraw_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderbookServiceClient) GetWallet(ctx context.Context, in *WalletRequest, opts ...grpc.CallOption) (*WalletResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WalletResponse)
+	err := c.cc.Invoke(ctx, OrderbookService_GetWallet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderbookServiceClient) GetPosition(ctx context.Context, in *PositionRequest, opts ...grpc.CallOption) (*PositionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PositionResponse)
+	err := c.cc.Invoke(ctx, OrderbookService_GetPosition_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderbookServiceClient) GetMarketDepth(ctx context.Context, in *MarketDepthRequest, opts ...grpc.CallOption) (*MarketDepth, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MarketDepth)
+	err := c.cc.Invoke(ctx, OrderbookService_GetMarketDepth_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderbookServiceClient) GetCashOutQuote(ctx context.Context, in *CashOutRequest, opts ...grpc.CallOption) (*CashOutQuote, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CashOutQuote)
+	err := c.cc.Invoke(ctx, OrderbookService_GetCashOutQuote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderbookServiceClient) CashOut(ctx context.Context, in *CashOutRequest, opts ...grpc.CallOption) (*CashOutResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CashOutResponse)
+	err := c.cc.Invoke(ctx, OrderbookService_CashOut_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrderbookServiceServer is the server API for OrderbookService service.
 // All implementations must embed UnimplementedOrderbookServiceServer
 // for forward compatibility.
 type OrderbookServiceServer interface {
 	RegisterMatch(context.Context, *MatchRequest) (*RegisterMatchResponse, error)
+	TransitionMarket(context.Context, *TransitionMarketRequest) (*MarketStateResponse, error)
+	GetMarketState(context.Context, *MarketStateRequest) (*MarketStateResponse, error)
+	SettleMatch(context.Context, *SettleMatchRequest) (*SettlementReport, error)
+	ResettleMatch(context.Context, *SettleMatchRequest) (*SettlementReport, error)
+	VoidMatch(context.Context, *VoidMatchRequest) (*SettlementReport, error)
+	PlaceOrder(context.Context, *PlaceOrderRequest) (*ExecutionReport, error)
+	CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error)
+	AmendOrder(context.Context, *AmendOrderRequest) (*AmendOrderResponse, error)
+	SetSelfTradePolicy(context.Context, *SelfTradePolicyRequest) (*SelfTradePolicyResponse, error)
+	SetSurplusPolicy(context.Context, *SurplusPolicyRequest) (*SurplusPolicyResponse, error)
+	SetMaxExposure(context.Context, *MaxExposureRequest) (*MaxExposureResponse, error)
+	Deposit(context.Context, *WalletTransferRequest) (*WalletResponse, error)
+	Withdraw(context.Context, *WalletTransferRequest) (*WalletResponse, error)
+	GetWallet(context.Context, *WalletRequest) (*WalletResponse, error)
+	GetPosition(context.Context, *PositionRequest) (*PositionResponse, error)
+	GetMarketDepth(context.Context, *MarketDepthRequest) (*MarketDepth, error)
+	GetCashOutQuote(context.Context, *CashOutRequest) (*CashOutQuote, error)
+	CashOut(context.Context, *CashOutRequest) (*CashOutResponse, error)
 	mustEmbedUnimplementedOrderbookServiceServer()
 }
 
@@ -65,6 +299,60 @@ type UnimplementedOrderbookServiceServer struct{}
 func (UnimplementedOrderbookServiceServer) RegisterMatch(context.Context, *MatchRequest) (*RegisterMatchResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RegisterMatch not implemented")
 }
+func (UnimplementedOrderbookServiceServer) TransitionMarket(context.Context, *TransitionMarketRequest) (*MarketStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransitionMarket not implemented")
+}
+func (UnimplementedOrderbookServiceServer) GetMarketState(context.Context, *MarketStateRequest) (*MarketStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMarketState not implemented")
+}
+func (UnimplementedOrderbookServiceServer) SettleMatch(context.Context, *SettleMatchRequest) (*SettlementReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SettleMatch not implemented")
+}
+func (UnimplementedOrderbookServiceServer) ResettleMatch(context.Context, *SettleMatchRequest) (*SettlementReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResettleMatch not implemented")
+}
+func (UnimplementedOrderbookServiceServer) VoidMatch(context.Context, *VoidMatchRequest) (*SettlementReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VoidMatch not implemented")
+}
+func (UnimplementedOrderbookServiceServer) PlaceOrder(context.Context, *PlaceOrderRequest) (*ExecutionReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlaceOrder not implemented")
+}
+func (UnimplementedOrderbookServiceServer) CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelOrder not implemented")
+}
+func (UnimplementedOrderbookServiceServer) AmendOrder(context.Context, *AmendOrderRequest) (*AmendOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AmendOrder not implemented")
+}
+func (UnimplementedOrderbookServiceServer) SetSelfTradePolicy(context.Context, *SelfTradePolicyRequest) (*SelfTradePolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSelfTradePolicy not implemented")
+}
+func (UnimplementedOrderbookServiceServer) SetSurplusPolicy(context.Context, *SurplusPolicyRequest) (*SurplusPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSurplusPolicy not implemented")
+}
+func (UnimplementedOrderbookServiceServer) SetMaxExposure(context.Context, *MaxExposureRequest) (*MaxExposureResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMaxExposure not implemented")
+}
+func (UnimplementedOrderbookServiceServer) Deposit(context.Context, *WalletTransferRequest) (*WalletResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Deposit not implemented")
+}
+func (UnimplementedOrderbookServiceServer) Withdraw(context.Context, *WalletTransferRequest) (*WalletResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Withdraw not implemented")
+}
+func (UnimplementedOrderbookServiceServer) GetWallet(context.Context, *WalletRequest) (*WalletResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWallet not implemented")
+}
+func (UnimplementedOrderbookServiceServer) GetPosition(context.Context, *PositionRequest) (*PositionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPosition not implemented")
+}
+func (UnimplementedOrderbookServiceServer) GetMarketDepth(context.Context, *MarketDepthRequest) (*MarketDepth, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMarketDepth not implemented")
+}
+func (UnimplementedOrderbookServiceServer) GetCashOutQuote(context.Context, *CashOutRequest) (*CashOutQuote, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCashOutQuote not implemented")
+}
+func (UnimplementedOrderbookServiceServer) CashOut(context.Context, *CashOutRequest) (*CashOutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CashOut not implemented")
+}
 func (UnimplementedOrderbookServiceServer) mustEmbedUnimplementedOrderbookServiceServer() {}
 func (UnimplementedOrderbookServiceServer) testEmbeddedByValue()                          {}
 
@@ -104,6 +392,330 @@ func _OrderbookService_RegisterMatch_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderbookService_TransitionMarket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransitionMarketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderbookServiceServer).TransitionMarket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderbookService_TransitionMarket_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderbookServiceServer).TransitionMarket(ctx, req.(*TransitionMarketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderbookService_GetMarketState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MarketStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderbookServiceServer).GetMarketState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderbookService_GetMarketState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderbookServiceServer).GetMarketState(ctx, req.(*MarketStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderbookService_SettleMatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SettleMatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderbookServiceServer).SettleMatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderbookService_SettleMatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderbookServiceServer).SettleMatch(ctx, req.(*SettleMatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderbookService_ResettleMatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SettleMatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderbookServiceServer).ResettleMatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderbookService_ResettleMatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderbookServiceServer).ResettleMatch(ctx, req.(*SettleMatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderbookService_VoidMatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VoidMatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderbookServiceServer).VoidMatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderbookService_VoidMatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderbookServiceServer).VoidMatch(ctx, req.(*VoidMatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderbookService_PlaceOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlaceOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderbookServiceServer).PlaceOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderbookService_PlaceOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderbookServiceServer).PlaceOrder(ctx, req.(*PlaceOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderbookService_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderbookServiceServer).CancelOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderbookService_CancelOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderbookServiceServer).CancelOrder(ctx, req.(*CancelOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderbookService_AmendOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AmendOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderbookServiceServer).AmendOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderbookService_AmendOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderbookServiceServer).AmendOrder(ctx, req.(*AmendOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderbookService_SetSelfTradePolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SelfTradePolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderbookServiceServer).SetSelfTradePolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderbookService_SetSelfTradePolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderbookServiceServer).SetSelfTradePolicy(ctx, req.(*SelfTradePolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderbookService_SetSurplusPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SurplusPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderbookServiceServer).SetSurplusPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderbookService_SetSurplusPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderbookServiceServer).SetSurplusPolicy(ctx, req.(*SurplusPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderbookService_SetMaxExposure_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MaxExposureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderbookServiceServer).SetMaxExposure(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderbookService_SetMaxExposure_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderbookServiceServer).SetMaxExposure(ctx, req.(*MaxExposureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderbookService_Deposit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WalletTransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderbookServiceServer).Deposit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderbookService_Deposit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderbookServiceServer).Deposit(ctx, req.(*WalletTransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderbookService_Withdraw_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WalletTransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderbookServiceServer).Withdraw(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderbookService_Withdraw_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderbookServiceServer).Withdraw(ctx, req.(*WalletTransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderbookService_GetWallet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WalletRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderbookServiceServer).GetWallet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderbookService_GetWallet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderbookServiceServer).GetWallet(ctx, req.(*WalletRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderbookService_GetPosition_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PositionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderbookServiceServer).GetPosition(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderbookService_GetPosition_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderbookServiceServer).GetPosition(ctx, req.(*PositionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderbookService_GetMarketDepth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MarketDepthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderbookServiceServer).GetMarketDepth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderbookService_GetMarketDepth_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderbookServiceServer).GetMarketDepth(ctx, req.(*MarketDepthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderbookService_GetCashOutQuote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CashOutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderbookServiceServer).GetCashOutQuote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderbookService_GetCashOutQuote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderbookServiceServer).GetCashOutQuote(ctx, req.(*CashOutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderbookService_CashOut_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CashOutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderbookServiceServer).CashOut(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderbookService_CashOut_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderbookServiceServer).CashOut(ctx, req.(*CashOutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // OrderbookService_ServiceDesc is the grpc.ServiceDesc for OrderbookService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,6 +727,78 @@ var OrderbookService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RegisterMatch",
 			Handler:    _OrderbookService_RegisterMatch_Handler,
 		},
+		{
+			MethodName: "TransitionMarket",
+			Handler:    _OrderbookService_TransitionMarket_Handler,
+		},
+		{
+			MethodName: "GetMarketState",
+			Handler:    _OrderbookService_GetMarketState_Handler,
+		},
+		{
+			MethodName: "SettleMatch",
+			Handler:    _OrderbookService_SettleMatch_Handler,
+		},
+		{
+			MethodName: "ResettleMatch",
+			Handler:    _OrderbookService_ResettleMatch_Handler,
+		},
+		{
+			MethodName: "VoidMatch",
+			Handler:    _OrderbookService_VoidMatch_Handler,
+		},
+		{
+			MethodName: "PlaceOrder",
+			Handler:    _OrderbookService_PlaceOrder_Handler,
+		},
+		{
+			MethodName: "CancelOrder",
+			Handler:    _OrderbookService_CancelOrder_Handler,
+		},
+		{
+			MethodName: "AmendOrder",
+			Handler:    _OrderbookService_AmendOrder_Handler,
+		},
+		{
+			MethodName: "SetSelfTradePolicy",
+			Handler:    _OrderbookService_SetSelfTradePolicy_Handler,
+		},
+		{
+			MethodName: "SetSurplusPolicy",
+			Handler:    _OrderbookService_SetSurplusPolicy_Handler,
+		},
+		{
+			MethodName: "SetMaxExposure",
+			Handler:    _OrderbookService_SetMaxExposure_Handler,
+		},
+		{
+			MethodName: "Deposit",
+			Handler:    _OrderbookService_Deposit_Handler,
+		},
+		{
+			MethodName: "Withdraw",
+			Handler:    _OrderbookService_Withdraw_Handler,
+		},
+		{
+			MethodName: "GetWallet",
+			Handler:    _OrderbookService_GetWallet_Handler,
+		},
+		{
+			MethodName: "GetPosition",
+			Handler:    _OrderbookService_GetPosition_Handler,
+		},
+		{
+			MethodName: "GetMarketDepth",
+			Handler:    _OrderbookService_GetMarketDepth_Handler,
+		},
+		{
+			MethodName: "GetCashOutQuote",
+			Handler:    _OrderbookService_GetCashOutQuote_Handler,
+		},
+		{
+			MethodName: "CashOut",
+			Handler:    _OrderbookService_CashOut_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/orderbook.proto",